@@ -1,9 +1,13 @@
 package main
 
 import (
+	_ "github.com/v2fly/geoip/plugin/cloudflare"
 	_ "github.com/v2fly/geoip/plugin/dbip"
+	_ "github.com/v2fly/geoip/plugin/ipapi"
 	_ "github.com/v2fly/geoip/plugin/maxmind"
 	_ "github.com/v2fly/geoip/plugin/plaintext"
+	_ "github.com/v2fly/geoip/plugin/redis"
 	_ "github.com/v2fly/geoip/plugin/special"
+	_ "github.com/v2fly/geoip/plugin/splunk"
 	_ "github.com/v2fly/geoip/plugin/v2ray"
 )
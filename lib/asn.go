@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"net/netip"
+	"regexp"
+	"strconv"
+)
+
+var asnEntryNamePattern = regexp.MustCompile(`^AS(\d+)$`)
+
+// KeyByASN returns the prefixes of the entries named in the ASN format, such
+// as AS13335, keyed by the AS number. Other entries are skipped.
+func (c *container) KeyByASN() (map[uint32][]netip.Prefix, error) {
+	asnPrefixes := make(map[uint32][]netip.Prefix)
+	err := c.ForEachEntry(func(entry *Entry) error {
+		matches := asnEntryNamePattern.FindStringSubmatch(entry.GetName())
+		if matches == nil {
+			return nil
+		}
+		asn, err := strconv.ParseUint(matches[1], 10, 32)
+		if err != nil {
+			return nil
+		}
+
+		if err := entry.buildIPSet(); err != nil {
+			return err
+		}
+		for prefix := range entry.Prefixes(IPBoth) {
+			asnPrefixes[uint32(asn)] = append(asnPrefixes[uint32(asn)], prefix)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asnPrefixes, nil
+}
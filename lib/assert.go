@@ -0,0 +1,122 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+
+	"go4.org/netipx"
+)
+
+// AssertEntryExists returns ErrEntryNotFound if the container has no entry
+// called name, e.g. to fail fast when an input source is broken.
+func (c *container) AssertEntryExists(name string) error {
+	if _, found := c.GetEntry(name); !found {
+		return fmt.Errorf("%w: %s", ErrEntryNotFound, name)
+	}
+	return nil
+}
+
+// AssertEntryHasAtLeast returns an error if the entry called name is missing
+// or has fewer than minPrefixes prefixes, IPv4 and IPv6 combined.
+func (c *container) AssertEntryHasAtLeast(name string, minPrefixes int) error {
+	entry, found := c.GetEntry(name)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrEntryNotFound, name)
+	}
+
+	ipv4Count, ipv6Count, err := entry.prefixCount()
+	if err != nil {
+		return err
+	}
+	if count := ipv4Count + ipv6Count; count < minPrefixes {
+		return fmt.Errorf("entry %s has %d prefixes, fewer than %d", entry.GetName(), count, minPrefixes)
+	}
+
+	return nil
+}
+
+type ConsistencyOption func() ConsistencyCheck
+
+type ConsistencyCheck int
+
+const (
+	checkNoOverlap ConsistencyCheck = iota + 1
+	checkBothIPTypes
+)
+
+// StrictNoOverlap makes AssertConsistency fail if any two entries overlap.
+func StrictNoOverlap() ConsistencyCheck {
+	return checkNoOverlap
+}
+
+// RequireBothIPTypes makes AssertConsistency fail if any entry has no IPv4
+// or no IPv6 prefix.
+func RequireBothIPTypes() ConsistencyCheck {
+	return checkBothIPTypes
+}
+
+// AssertConsistency checks that every entry has a name and only valid,
+// masked prefixes. With RequireBothIPTypes it also checks that every entry
+// has both IPv4 and IPv6 prefixes, and with StrictNoOverlap that no two
+// entries overlap. All problems found are returned joined together.
+func (c *container) AssertConsistency(opts ...ConsistencyOption) error {
+	strictNoOverlap, requireBothIPTypes := false, false
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		switch opt() {
+		case checkNoOverlap:
+			strictNoOverlap = true
+		case checkBothIPTypes:
+			requireBothIPTypes = true
+		}
+	}
+
+	var errs []error
+	names := c.names()
+	for _, name := range names {
+		entry := c.entries[name]
+		if name == "" || entry.GetName() == "" {
+			errs = append(errs, fmt.Errorf("entry %q has an empty name", name))
+		}
+
+		if err := entry.buildIPSet(); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", name, err))
+			continue
+		}
+		if requireBothIPTypes && !entry.hasIPv4Set() {
+			errs = append(errs, fmt.Errorf("entry %s has no IPv4 set", name))
+		}
+		if requireBothIPTypes && !entry.hasIPv6Set() {
+			errs = append(errs, fmt.Errorf("entry %s has no IPv6 set", name))
+		}
+
+		for prefix := range entry.Prefixes(IPBoth) {
+			if !prefix.IsValid() || prefix != prefix.Masked() {
+				errs = append(errs, fmt.Errorf("entry %s: %w: %s", name, ErrInvalidPrefix, prefix))
+			}
+		}
+	}
+
+	if strictNoOverlap {
+		sets := make([]*netipx.IPSet, len(names))
+		for i, name := range names {
+			set, err := c.entries[name].ipSet()
+			if err != nil {
+				return errors.Join(append(errs, fmt.Errorf("entry %s: %w", name, err))...)
+			}
+			sets[i] = set
+		}
+
+		for i, name := range names {
+			for j, otherName := range names[i+1:] {
+				if sets[i].Overlaps(sets[i+1+j]) {
+					errs = append(errs, fmt.Errorf("entries %s and %s overlap", name, otherName))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
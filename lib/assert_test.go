@@ -0,0 +1,38 @@
+package lib
+
+import "testing"
+
+func TestAssertConsistency(t *testing.T) {
+	c, err := NewContainerFromMap(map[string][]string{
+		"CN": {"1.0.1.0/24", "240e::/20"},
+		"US": {"8.8.8.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(NewEntry("EMPTY")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries with one IP type or none at all are consistent by default
+	if err := c.AssertConsistency(); err != nil {
+		t.Errorf("AssertConsistency() = %v, want nil", err)
+	}
+	if err := c.AssertConsistency(RequireBothIPTypes); err == nil {
+		t.Error("AssertConsistency(RequireBothIPTypes) = nil, want an error for US and EMPTY")
+	}
+
+	if err := c.AssertConsistency(StrictNoOverlap); err != nil {
+		t.Errorf("AssertConsistency(StrictNoOverlap) = %v, want nil", err)
+	}
+	overlapping := NewEntry("AU")
+	if err := overlapping.AddPrefix("8.8.0.0/16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(overlapping); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AssertConsistency(StrictNoOverlap); err == nil {
+		t.Error("AssertConsistency(StrictNoOverlap) = nil, want an error for AU and US")
+	}
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"math/big"
+	"net/netip"
+	"slices"
+)
+
+// AuditReport holds quality metrics of an entry.
+type AuditReport struct {
+	Name              string
+	IPv4PrefixCount   int
+	IPv6PrefixCount   int
+	IPCount           *big.Int
+	LongestPrefixLen  int
+	ShortestPrefixLen int
+	HasBogon          bool
+	HasLoopback       bool
+	HasOverlap        bool
+}
+
+// Audit returns quality metrics of the entry. Because the IP sets of an entry
+// never hold overlapping prefixes, HasOverlap is computed from the prefixes
+// recorded with a source (see AddSourcedPrefixes and AddPrefixWithPriority).
+func (e *Entry) Audit() (AuditReport, error) {
+	report := AuditReport{
+		Name:              e.GetName(),
+		IPCount:           new(big.Int),
+		LongestPrefixLen:  -1,
+		ShortestPrefixLen: -1,
+	}
+
+	if err := e.buildIPSet(); err != nil {
+		return AuditReport{}, err
+	}
+
+	var prefixes []netip.Prefix
+	if e.hasIPv4Set() {
+		ipv4Prefixes := e.ipv4Set.Prefixes()
+		report.IPv4PrefixCount = len(ipv4Prefixes)
+		report.IPCount.Add(report.IPCount, countIPs(e.ipv4Set))
+		report.HasBogon = report.HasBogon || e.ipv4Set.Overlaps(bogonSet())
+		report.HasLoopback = report.HasLoopback || e.ipv4Set.Overlaps(loopbackSet())
+		prefixes = append(prefixes, ipv4Prefixes...)
+	}
+	if e.hasIPv6Set() {
+		ipv6Prefixes := e.ipv6Set.Prefixes()
+		report.IPv6PrefixCount = len(ipv6Prefixes)
+		report.IPCount.Add(report.IPCount, countIPs(e.ipv6Set))
+		report.HasBogon = report.HasBogon || e.ipv6Set.Overlaps(bogonSet())
+		report.HasLoopback = report.HasLoopback || e.ipv6Set.Overlaps(loopbackSet())
+		prefixes = append(prefixes, ipv6Prefixes...)
+	}
+
+	for _, prefix := range prefixes {
+		if report.LongestPrefixLen < 0 || prefix.Bits() > report.LongestPrefixLen {
+			report.LongestPrefixLen = prefix.Bits()
+		}
+		if report.ShortestPrefixLen < 0 || prefix.Bits() < report.ShortestPrefixLen {
+			report.ShortestPrefixLen = prefix.Bits()
+		}
+	}
+
+	sourced, err := e.sourcedPrefixes()
+	if err != nil {
+		return AuditReport{}, err
+	}
+	report.HasOverlap = hasOverlap(sourced)
+
+	return report, nil
+}
+
+// hasOverlap reports whether any two of the prefixes overlap.
+func hasOverlap(prefixes []netip.Prefix) bool {
+	slices.SortFunc(prefixes, comparePrefix)
+	for i := 1; i < len(prefixes); i++ {
+		if prefixes[i-1].Overlaps(prefixes[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"net/netip"
+	"sync"
+
+	"go4.org/netipx"
+)
+
+// bogonCIDRs are the reserved and special-purpose ranges
+// which should never be routed on the public internet.
+var bogonCIDRs = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.88.99.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"100::/64",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+}
+
+var loopbackCIDRs = []string{
+	"127.0.0.0/8",
+	"::1/128",
+}
+
+var (
+	bogonSet    = sync.OnceValue(func() *netipx.IPSet { return mustBuildIPSet(bogonCIDRs) })
+	loopbackSet = sync.OnceValue(func() *netipx.IPSet { return mustBuildIPSet(loopbackCIDRs) })
+)
+
+func mustBuildIPSet(cidrs []string) *netipx.IPSet {
+	var builder netipx.IPSetBuilder
+	for _, cidr := range cidrs {
+		builder.AddPrefix(netip.MustParsePrefix(cidr))
+	}
+	set, err := builder.IPSet()
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
@@ -0,0 +1,79 @@
+package lib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrUnknownModTime = errors.New("unknown modification time")
+
+// WriteCheckpoint writes a gob encoded snapshot of all entries to path.
+func (c *container) WriteCheckpoint(path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := c.Serialize(file, "gob"); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// LoadCheckpoint replaces all entries of the container with the ones
+// stored in the checkpoint file at path.
+func (c *container) LoadCheckpoint(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.Deserialize(file, "gob")
+}
+
+// GetModTime returns the latest modification time of the local files or
+// directories in uris. Remote URLs have no known modification time, so
+// ErrUnknownModTime is returned for them.
+func GetModTime(uris ...string) (time.Time, error) {
+	var modTime time.Time
+	for _, uri := range uris {
+		if uri == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(uri), "http://") || strings.HasPrefix(strings.ToLower(uri), "https://") {
+			return time.Time{}, ErrUnknownModTime
+		}
+
+		err := filepath.WalkDir(uri, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+			return nil
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return modTime, nil
+}
@@ -0,0 +1,180 @@
+package lib
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var codecMap = make(map[string]Codec)
+
+// Codec encodes and decodes the serialized form of a container.
+type Codec interface {
+	Encode(w io.Writer, v any) error
+	Decode(r io.Reader, v any) error
+}
+
+func init() {
+	RegisterCodec("gob", gobCodec{})
+	RegisterCodec("json", jsonCodec{})
+	RegisterCodec("msgpack", msgpackCodec{})
+}
+
+func RegisterCodec(name string, c Codec) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := codecMap[name]; ok {
+		return ErrDuplicatedCodec
+	}
+	codecMap[name] = c
+	return nil
+}
+
+func getCodec(name string) (Codec, error) {
+	c, found := codecMap[strings.ToLower(strings.TrimSpace(name))]
+	if !found {
+		return nil, ErrUnknownCodec
+	}
+	return c, nil
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(w io.Writer, v any) error {
+	return gob.NewEncoder(w).Encode(v)
+}
+
+func (gobCodec) Decode(r io.Reader, v any) error {
+	return gob.NewDecoder(r).Decode(v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Encode(w io.Writer, v any) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	return encoder.Encode(v)
+}
+
+func (msgpackCodec) Decode(r io.Reader, v any) error {
+	decoder := msgpack.NewDecoder(r)
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+type serializedEntry struct {
+	Name         string             `json:"name"`
+	Prefixes     []netip.Prefix     `json:"prefixes"`
+	LastModified time.Time          `json:"lastModified"`
+	Sources      []serializedSource `json:"sources,omitempty"`
+}
+
+// serializedSource holds the recorded source of a prefix and the priorities
+// of all sources which added it.
+type serializedSource struct {
+	Prefix     netip.Prefix   `json:"prefix"`
+	Source     string         `json:"source"`
+	Priorities map[string]int `json:"priorities,omitempty"`
+}
+
+// Serialize writes all entries of the container to w using the codec
+// registered as codecName. The recorded sources and priorities of the
+// prefixes are kept, so that Deserialize restores them.
+func (c *container) Serialize(w io.Writer, codecName string) error {
+	codec, err := getCodec(codecName)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]serializedEntry, 0, c.Len())
+	for _, name := range c.names() {
+		entry, _ := c.GetEntry(name)
+		if err := entry.buildIPSet(); err != nil {
+			return err
+		}
+
+		prefixes := make([]netip.Prefix, 0, 1024)
+		if entry.hasIPv4Set() {
+			prefixes = append(prefixes, entry.ipv4Set.Prefixes()...)
+		}
+		if entry.hasIPv6Set() {
+			prefixes = append(prefixes, entry.ipv6Set.Prefixes()...)
+		}
+
+		var sources []serializedSource
+		for _, prefix := range slices.SortedFunc(maps.Keys(entry.sources), comparePrefix) {
+			sources = append(sources, serializedSource{
+				Prefix:     prefix,
+				Source:     entry.sources[prefix],
+				Priorities: entry.priorities[prefix],
+			})
+		}
+
+		entries = append(entries, serializedEntry{
+			Name:         entry.GetName(),
+			Prefixes:     prefixes,
+			LastModified: entry.GetLastModified(),
+			Sources:      sources,
+		})
+	}
+
+	return codec.Encode(w, entries)
+}
+
+// Deserialize replaces all entries of the container with the ones read
+// from r using the codec registered as codecName.
+func (c *container) Deserialize(r io.Reader, codecName string) error {
+	codec, err := getCodec(codecName)
+	if err != nil {
+		return err
+	}
+
+	var entries []serializedEntry
+	if err := codec.Decode(r, &entries); err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*Entry, len(entries))
+	for _, se := range entries {
+		entry := NewEntry(se.Name)
+		for _, prefix := range se.Prefixes {
+			if err := entry.AddPrefix(prefix); err != nil {
+				return err
+			}
+		}
+		entry.SetLastModified(se.LastModified)
+		for _, source := range se.Sources {
+			if entry.sources == nil {
+				entry.sources = make(map[netip.Prefix]string)
+			}
+			entry.sources[source.Prefix] = source.Source
+			for name, priority := range source.Priorities {
+				entry.addPriority(source.Prefix, name, priority)
+			}
+		}
+		loaded[entry.GetName()] = entry
+	}
+
+	c.entries = loaded
+	c.lookupIndex = nil
+
+	return nil
+}
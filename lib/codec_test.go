@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"bytes"
+	"maps"
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	for _, codecName := range []string{"gob", "json", "msgpack"} {
+		t.Run(codecName, func(t *testing.T) {
+			entry := NewEntry("TEST")
+			if err := entry.AddPrefixWithPriority(netip.MustParsePrefix("10.0.0.0/8"), "a.txt", 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := entry.AddPrefixWithPriority(netip.MustParsePrefix("10.0.0.0/8"), "b.txt", 2); err != nil {
+				t.Fatal(err)
+			}
+			if err := entry.AddPrefix("2001:db8::/32"); err != nil {
+				t.Fatal(err)
+			}
+			entry.SetLastModified(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+			src := NewContainer()
+			if err := src.Add(entry); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := src.Serialize(&buf, codecName); err != nil {
+				t.Fatalf("Serialize: %v", err)
+			}
+			dst := NewContainer()
+			if err := dst.Deserialize(&buf, codecName); err != nil {
+				t.Fatalf("Deserialize: %v", err)
+			}
+
+			got, found := dst.GetEntry("TEST")
+			if !found {
+				t.Fatal("entry TEST not found after Deserialize")
+			}
+			wantPrefixes := parsePrefixes(t, []string{"10.0.0.0/8", "2001:db8::/32"})
+			if gotPrefixes := slices.Collect(got.Prefixes(IPBoth)); !slices.Equal(gotPrefixes, wantPrefixes) {
+				t.Errorf("prefixes = %v, want %v", gotPrefixes, wantPrefixes)
+			}
+			if !got.GetLastModified().Equal(entry.GetLastModified()) {
+				t.Errorf("lastModified = %v, want %v", got.GetLastModified(), entry.GetLastModified())
+			}
+			if !maps.Equal(got.GetPrefixSources(), entry.GetPrefixSources()) {
+				t.Errorf("sources = %v, want %v", got.GetPrefixSources(), entry.GetPrefixSources())
+			}
+
+			prefix := netip.MustParsePrefix("10.0.0.0/8")
+			if !maps.Equal(got.priorities[prefix], entry.priorities[prefix]) {
+				t.Errorf("priorities = %v, want %v", got.priorities[prefix], entry.priorities[prefix])
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/exec"
+	"strings"
 )
 
 func GetRemoteURLContent(url string) ([]byte, error) {
@@ -32,3 +35,28 @@ func GetRemoteURLReader(url string) (io.ReadCloser, error) {
 
 	return resp.Body, nil
 }
+
+// PostProcessFile runs command on the generated file at path, replacing
+// every {file} in command with path, and removes the file afterwards if
+// deleteOriginal is true. The command is split on whitespace and is not
+// run through a shell. Nothing is done if command is empty.
+func PostProcessFile(command, path string, deleteOriginal bool) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil
+	}
+	for idx, arg := range args {
+		args[idx] = strings.ReplaceAll(arg, "{file}", path)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to post-process %s: %w: %s", path, err, strings.TrimSpace(string(output)))
+	}
+
+	if deleteOriginal {
+		return os.Remove(path)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -53,8 +54,44 @@ func createOutputConfig(id string, action Action, data json.RawMessage) (OutputC
 }
 
 type config struct {
-	Input  []*inputConvConfig  `json:"input"`
-	Output []*outputConvConfig `json:"output"`
+	Input           []*inputConvConfig  `json:"input"`
+	Output          []*outputConvConfig `json:"output"`
+	OutputOrder     []string            `json:"outputOrder"`
+	ParallelOutputs bool                `json:"parallelOutputs"`
+	EntryRenames    map[string]string   `json:"entryRenames"`
+	EnvFile         string              `json:"envFile"`
+
+	PrometheusPushgateway string `json:"prometheusPushgateway"`
+	JobName               string `json:"jobName"`
+	InstanceLabel         string `json:"instanceLabel"`
+}
+
+// sortOutput orders the output converters by the position of their types in
+// OutputOrder. Types not listed run last, and converters of the same type keep
+// the order of the output array.
+func (c *config) sortOutput() {
+	if len(c.OutputOrder) == 0 {
+		return
+	}
+
+	rank := make(map[string]int, len(c.OutputOrder))
+	for idx, typ := range c.OutputOrder {
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if _, found := rank[typ]; !found {
+			rank[typ] = idx
+		}
+	}
+
+	getRank := func(o *outputConvConfig) int {
+		if idx, found := rank[strings.ToLower(o.iType)]; found {
+			return idx
+		}
+		return len(c.OutputOrder)
+	}
+
+	slices.SortStableFunc(c.Output, func(a, b *outputConvConfig) int {
+		return getRank(a) - getRank(b)
+	})
 }
 
 type inputConvConfig struct {
@@ -74,7 +111,7 @@ func (i *inputConvConfig) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	if !ActionsRegistry[temp.Action] {
+	if !ValidAction(temp.Action) {
 		return fmt.Errorf("invalid action %s in type %s", temp.Action, temp.Type)
 	}
 
@@ -111,7 +148,7 @@ func (i *outputConvConfig) UnmarshalJSON(data []byte) error {
 		temp.Action = ActionOutput
 	}
 
-	if !ActionsRegistry[temp.Action] {
+	if !ValidAction(temp.Action) {
 		return fmt.Errorf("invalid action %s in type %s", temp.Action, temp.Type)
 	}
 
@@ -1,8 +1,18 @@
 package lib
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"log"
+	"maps"
+	"math/big"
+	"net/netip"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"go4.org/netipx"
 )
@@ -13,10 +23,52 @@ type Container interface {
 	Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOption) error
 	Len() int
 	Loop() <-chan *Entry
+	OverlapsWith(name string, other Container, otherName string) (bool, []netip.Prefix, error)
+	Diff(other Container) (DiffResult, error)
+	GetLastModified() time.Time
+	GroupByRIR() (map[string]Container, error)
+	GroupByPrefix(prefixLen int) (map[string]Container, error)
+	KeyByASN() (map[uint32][]netip.Prefix, error)
+	WriteCheckpoint(path string) error
+	LoadCheckpoint(path string) error
+	ForEachEntry(fn func(*Entry) error) error
+	ForEachEntryParallel(concurrency int, fn func(*Entry) error) error
+	Serialize(w io.Writer, codecName string) error
+	WriteTo(w io.Writer, format string) error
+	Deserialize(r io.Reader, codecName string) error
+	RenameEntry(oldName, newName string, force bool) error
+	SwapEntries(nameA, nameB string) error
+	Compact() ([]CompactionReport, error)
+	ApplyWantedList(want []string) (Container, error)
+	ApplyExcludedList(exclude []string) (Container, error)
+	Prune(retainList []string) error
+	ClearAll() error
+	Reset() error
+	PruneByPredicate(keep func(name string) bool) int
+	ApplyTransformers(names []string, args map[string]any) error
+	TopNByPrefixCount(n int) ([]string, error)
+	TotalPrefixCount() (ipv4Count int, ipv6Count int, err error)
+	TotalIPCount() (*big.Int, error)
+	FilterByMinIPCount(minIPs *big.Int) (Container, error)
+	Snapshot() (Container, error)
+	RestoreSnapshot(snap Container) error
+	EstimatedMemoryBytes() int64
+	ImportFromMMDB(path string, countryCodeField []string) error
+	ImportFromCSV(r io.Reader, networkCol, countryCol int, hasHeader bool, opts ...CSVOption) error
+	LookupIP(ip netip.Addr) (*Entry, bool)
+	BuildLookupIndex() error
+	AssertEntryExists(name string) error
+	AssertEntryHasAtLeast(name string, minPrefixes int) error
+	AssertConsistency(opts ...ConsistencyOption) error
+	OrderedLoop(less func(a, b *Entry) bool) (iter.Seq[*Entry], error)
+	ForEachPrefix(fn func(country string, prefix netip.Prefix) error) error
+	IterPrefixes() iter.Seq2[string, netip.Prefix]
+	WithNamespace(namespace string) Container
 }
 
 type container struct {
-	entries map[string]*Entry
+	entries     map[string]*Entry
+	lookupIndex []lookupRange
 }
 
 func NewContainer() Container {
@@ -25,6 +77,101 @@ func NewContainer() Container {
 	}
 }
 
+// NewContainerFromMap creates a container from a map of entry names
+// to lists of IP addresses or CIDRs.
+func NewContainerFromMap(data map[string][]string) (Container, error) {
+	c := NewContainer()
+
+	names := slices.Sorted(maps.Keys(data))
+	for _, name := range names {
+		entry := NewEntry(name)
+		for _, cidr := range data[name] {
+			if err := entry.AddPrefix(cidr); err != nil {
+				return nil, fmt.Errorf("entry %s: %w", entry.GetName(), err)
+			}
+		}
+		if err := c.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// MergeContainers merges the entries of all containers into a new container.
+// Entries with the same name are combined, and the given containers are left
+// unchanged.
+func MergeContainers(containers ...Container) (Container, error) {
+	merged := NewContainer()
+	for _, c := range containers {
+		if c == nil {
+			continue
+		}
+		err := c.ForEachEntry(func(entry *Entry) error {
+			return merged.Add(entry.clone())
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// Snapshot returns a deep copy of the container.
+func (c *container) Snapshot() (Container, error) {
+	return MergeContainers(c)
+}
+
+// RestoreSnapshot replaces all entries of the container with copies of the
+// entries of snap, so that snap can be restored again later.
+func (c *container) RestoreSnapshot(snap Container) error {
+	if snap == nil {
+		return errors.New("snapshot must not be nil")
+	}
+
+	restored, err := MergeContainers(snap)
+	if err != nil {
+		return err
+	}
+	c.entries = restored.(*container).entries
+	c.lookupIndex = nil
+
+	return nil
+}
+
+// IntersectContainers returns a new container with the entries present in
+// both a and b, each holding the addresses covered by both of them. Entries
+// whose intersection is empty are left out.
+func IntersectContainers(a, b Container) (Container, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("containers must not be nil")
+	}
+
+	intersected := NewContainer()
+	err := a.ForEachEntry(func(entry *Entry) error {
+		other, found := b.GetEntry(entry.GetName())
+		if !found {
+			return nil
+		}
+
+		intersection, err := entry.Intersection(other)
+		if err != nil {
+			return err
+		}
+		// GetIPVersion only fails for an empty entry here
+		if _, err := intersection.GetIPVersion(); err != nil {
+			return nil
+		}
+
+		return intersected.Add(intersection)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return intersected, nil
+}
+
 func (c *container) isValid() bool {
 	return c.entries != nil
 }
@@ -47,6 +194,27 @@ func (c *container) Len() int {
 	return len(c.entries)
 }
 
+// GetLastModified returns the latest modification time of all entries.
+func (c *container) GetLastModified() time.Time {
+	var lastModified time.Time
+	for _, entry := range c.entries {
+		if entry.GetLastModified().After(lastModified) {
+			lastModified = entry.GetLastModified()
+		}
+	}
+	return lastModified
+}
+
+// names returns the names of all entries in sorted order.
+func (c *container) names() []string {
+	names := make([]string, 0, c.Len())
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 func (c *container) Loop() <-chan *Entry {
 	ch := make(chan *Entry, c.Len())
 	go func() {
@@ -58,7 +226,90 @@ func (c *container) Loop() <-chan *Entry {
 	return ch
 }
 
+// ForEachEntry calls fn for each entry in sorted name order,
+// and stops at the first error.
+func (c *container) ForEachEntry(fn func(*Entry) error) error {
+	for _, name := range c.names() {
+		if err := fn(c.entries[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachPrefix calls fn for each prefix of each entry, in sorted name order
+// and with the prefixes of each entry sorted, and stops at the first error.
+func (c *container) ForEachPrefix(fn func(country string, prefix netip.Prefix) error) error {
+	return c.ForEachEntry(func(entry *Entry) error {
+		if err := entry.buildIPSet(); err != nil {
+			return err
+		}
+		for prefix := range entry.Prefixes(IPBoth) {
+			if err := fn(entry.GetName(), prefix); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IterPrefixes returns an iterator over the names and prefixes of all
+// entries, in the same order as ForEachPrefix. Entries whose IP sets
+// cannot be built are skipped.
+func (c *container) IterPrefixes() iter.Seq2[string, netip.Prefix] {
+	return func(yield func(string, netip.Prefix) bool) {
+		for _, name := range c.names() {
+			entry := c.entries[name]
+			if err := entry.buildIPSet(); err != nil {
+				continue
+			}
+			for prefix := range entry.Prefixes(IPBoth) {
+				if !yield(name, prefix) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ForEachEntryParallel calls fn for each entry with up to concurrency
+// goroutines, and returns all errors joined together.
+func (c *container) ForEachEntryParallel(concurrency int, fn func(*Entry) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, name := range c.names() {
+		entry := c.entries[name]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(entry); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("entry %s: %w", entry.GetName(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
 func (c *container) Add(entry *Entry, opts ...IgnoreIPOption) error {
+	c.lookupIndex = nil
+
 	var ignoreIPType IPType
 	for _, opt := range opts {
 		if opt != nil {
@@ -71,6 +322,11 @@ func (c *container) Add(entry *Entry, opts ...IgnoreIPOption) error {
 
 	switch found {
 	case true:
+		if entry.GetLastModified().After(val.GetLastModified()) {
+			val.SetLastModified(entry.GetLastModified())
+		}
+		val.mergeSources(entry, ignoreIPType)
+
 		var ipv4set, ipv6set *netipx.IPSet
 		var err4, err6 error
 		if entry.hasIPv4Builder() {
@@ -85,6 +341,7 @@ func (c *container) Add(entry *Entry, opts ...IgnoreIPOption) error {
 				return err6
 			}
 		}
+		val.resetIPSet()
 		switch ignoreIPType {
 		case IPv4:
 			if !val.hasIPv6Builder() {
@@ -108,22 +365,235 @@ func (c *container) Add(entry *Entry, opts ...IgnoreIPOption) error {
 		}
 
 	case false:
+		entry.resetIPSet()
 		switch ignoreIPType {
 		case IPv4:
 			entry.ipv4Builder = nil
 		case IPv6:
 			entry.ipv6Builder = nil
 		}
+		maps.DeleteFunc(entry.sources, func(prefix netip.Prefix, _ string) bool {
+			return isIgnoredPrefix(prefix, ignoreIPType)
+		})
+		maps.DeleteFunc(entry.priorities, func(prefix netip.Prefix, _ map[string]int) bool {
+			return isIgnoredPrefix(prefix, ignoreIPType)
+		})
 		c.entries[name] = entry
 	}
 
 	return nil
 }
 
+// SwapEntries exchanges the prefixes of the entries nameA and nameB. Only
+// the builders and IP sets are exchanged: the entries keep their names,
+// recorded sources, priorities and modification times.
+func (c *container) SwapEntries(nameA, nameB string) error {
+	entryA, found := c.GetEntry(nameA)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrEntryNotFound, nameA)
+	}
+	entryB, found := c.GetEntry(nameB)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrEntryNotFound, nameB)
+	}
+
+	entryA.ipv4Builder, entryB.ipv4Builder = entryB.ipv4Builder, entryA.ipv4Builder
+	entryA.ipv6Builder, entryB.ipv6Builder = entryB.ipv6Builder, entryA.ipv6Builder
+	entryA.ipv4Set, entryB.ipv4Set = entryB.ipv4Set, entryA.ipv4Set
+	entryA.ipv6Set, entryB.ipv6Set = entryB.ipv6Set, entryA.ipv6Set
+	c.lookupIndex = nil
+
+	return nil
+}
+
+// ApplyWantedList returns a new container holding copies of the entries in
+// want, like the wantedList option of output converters. Wanted entries
+// which are not in the container are skipped.
+func (c *container) ApplyWantedList(want []string) (Container, error) {
+	filtered := NewContainer()
+	for _, name := range want {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		entry, found := c.GetEntry(name)
+		if !found {
+			log.Printf("❌ entry %s not found\n", name)
+			continue
+		}
+		if err := filtered.Add(entry.clone()); err != nil {
+			return nil, err
+		}
+	}
+	return filtered, nil
+}
+
+// ApplyExcludedList returns a new container holding copies of the entries
+// not in exclude, like the excludedList option of output converters.
+func (c *container) ApplyExcludedList(exclude []string) (Container, error) {
+	excludeMap := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+			excludeMap[name] = true
+		}
+	}
+
+	filtered := NewContainer()
+	err := c.ForEachEntry(func(entry *Entry) error {
+		if excludeMap[entry.GetName()] {
+			return nil
+		}
+		return filtered.Add(entry.clone())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// ClearAll removes all entries from the container.
+func (c *container) ClearAll() error {
+	c.entries = make(map[string]*Entry)
+	c.lookupIndex = nil
+	return nil
+}
+
+// Reset is an alias of ClearAll.
+func (c *container) Reset() error {
+	return c.ClearAll()
+}
+
+// Prune removes all entries not in retainList, so an empty retainList
+// leaves the container empty.
+func (c *container) Prune(retainList []string) error {
+	retain := make(map[string]bool, len(retainList))
+	for _, name := range retainList {
+		if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+			retain[name] = true
+		}
+	}
+
+	c.PruneByPredicate(func(name string) bool {
+		return retain[name]
+	})
+
+	return nil
+}
+
+// PruneByPredicate removes the entries for which keep returns false, and
+// returns the number of entries removed.
+func (c *container) PruneByPredicate(keep func(name string) bool) int {
+	removed := 0
+	for name := range c.entries {
+		if !keep(name) {
+			delete(c.entries, name)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.lookupIndex = nil
+	}
+	return removed
+}
+
+// CompactionReport is the result of compacting an entry. Before is the
+// number of prefixes recorded with a source (see AddSourcedPrefixes), or
+// the number of prefixes of the IP sets if none is recorded, After the
+// number of prefixes left after compacting, and Removed the difference of
+// the two.
+type CompactionReport struct {
+	Entry   string
+	Before  int
+	After   int
+	Removed int
+}
+
+// Compact reduces every entry to its minimal set of prefixes with
+// Entry.Compact. Rather than only an error, it also returns a report for
+// each entry in name order, so that callers can see which entries were
+// reduced.
+func (c *container) Compact() ([]CompactionReport, error) {
+	reports := make([]CompactionReport, 0, c.Len())
+	err := c.ForEachEntry(func(entry *Entry) error {
+		sourced, err := entry.sourcedPrefixes()
+		if err != nil {
+			return err
+		}
+		before := len(sourced)
+		if before == 0 {
+			// The builders merge prefixes added without a source, so only
+			// the prefixes of the IP sets are known
+			ipv4Count, ipv6Count, err := entry.prefixCount()
+			if err != nil {
+				return err
+			}
+			before = ipv4Count + ipv6Count
+		}
+
+		if err := entry.Compact(); err != nil {
+			return err
+		}
+		ipv4Count, ipv6Count, err := entry.prefixCount()
+		if err != nil {
+			return err
+		}
+
+		after := ipv4Count + ipv6Count
+		reports = append(reports, CompactionReport{
+			Entry:   entry.GetName(),
+			Before:  before,
+			After:   after,
+			Removed: max(before-after, 0),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// RenameEntry renames the entry oldName to newName. If newName is already
+// taken, ErrEntryAlreadyExists is returned unless force is true, in which
+// case the two entries are merged. newName must follow the same rules as
+// Entry.SetName, so it may include namespaces like "MYTENANT/CN".
+func (c *container) RenameEntry(oldName, newName string, force bool) error {
+	entry, found := c.GetEntry(oldName)
+	if !found {
+		return ErrEntryNotFound
+	}
+
+	oldName = entry.GetName()
+	newName = strings.ToUpper(strings.TrimSpace(newName))
+	if !validEntryName(newName) {
+		return fmt.Errorf("%w: %q", ErrInvalidEntryName, newName)
+	}
+	if newName == oldName {
+		return nil
+	}
+
+	if _, found := c.GetEntry(newName); found && !force {
+		return ErrEntryAlreadyExists
+	}
+
+	if err := entry.SetName(newName); err != nil {
+		return err
+	}
+	delete(c.entries, oldName)
+
+	return c.Add(entry)
+}
+
 func (c *container) Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOption) error {
+	c.lookupIndex = nil
+
 	name := entry.GetName()
 	val, found := c.GetEntry(name)
 	if !found {
+		// Nothing to clear
+		if rCase == CaseClearEntry {
+			return nil
+		}
 		return fmt.Errorf("entry %s not found", name)
 	}
 
@@ -151,6 +621,7 @@ func (c *container) Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOptio
 			}
 		}
 
+		val.resetIPSet()
 		switch ignoreIPType {
 		case IPv4:
 			if !val.hasIPv6Builder() {
@@ -174,6 +645,7 @@ func (c *container) Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOptio
 		}
 
 	case CaseRemoveEntry:
+		val.resetIPSet()
 		switch ignoreIPType {
 		case IPv4:
 			val.ipv6Builder = nil
@@ -183,9 +655,63 @@ func (c *container) Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOptio
 			delete(c.entries, name)
 		}
 
+	case CaseClearEntry:
+		val.resetIPSet()
+		switch ignoreIPType {
+		case IPv4:
+			val.ipv6Builder = new(netipx.IPSetBuilder)
+		case IPv6:
+			val.ipv4Builder = new(netipx.IPSetBuilder)
+		default:
+			val.ipv4Builder = new(netipx.IPSetBuilder)
+			val.ipv6Builder = new(netipx.IPSetBuilder)
+		}
+		maps.DeleteFunc(val.sources, func(prefix netip.Prefix, _ string) bool {
+			return !isIgnoredPrefix(prefix, ignoreIPType)
+		})
+		maps.DeleteFunc(val.priorities, func(prefix netip.Prefix, _ map[string]int) bool {
+			return !isIgnoredPrefix(prefix, ignoreIPType)
+		})
+
 	default:
 		return fmt.Errorf("unknown remove case %d", rCase)
 	}
 
 	return nil
 }
+
+// OverlapsWith reports whether the entry called name in c overlaps with
+// the entry called otherName in other, and returns the overlapping prefixes.
+func (c *container) OverlapsWith(name string, other Container, otherName string) (bool, []netip.Prefix, error) {
+	entry, found := c.GetEntry(name)
+	if !found {
+		return false, nil, fmt.Errorf("entry %s not found", name)
+	}
+	if other == nil {
+		return false, nil, fmt.Errorf("container of entry %s is nil", otherName)
+	}
+	otherEntry, found := other.GetEntry(otherName)
+	if !found {
+		return false, nil, fmt.Errorf("entry %s not found", otherName)
+	}
+
+	set, err := entry.ipSet()
+	if err != nil {
+		return false, nil, err
+	}
+	otherSet, err := otherEntry.ipSet()
+	if err != nil {
+		return false, nil, err
+	}
+
+	var builder netipx.IPSetBuilder
+	builder.AddSet(set)
+	builder.Intersect(otherSet)
+	intersection, err := builder.IPSet()
+	if err != nil {
+		return false, nil, err
+	}
+
+	prefixes := intersection.Prefixes()
+	return len(prefixes) > 0, prefixes, nil
+}
@@ -0,0 +1,173 @@
+package lib
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+)
+
+type namedPrefix struct {
+	name   string
+	prefix netip.Prefix
+}
+
+func TestContainerIterPrefixes(t *testing.T) {
+	c, err := NewContainerFromMap(map[string][]string{
+		"US": {"2001:db8::/32", "8.8.8.0/24", "3.0.0.0/8"},
+		"CN": {"240e::/20", "1.0.1.0/24", "1.0.2.0/23"},
+		"AU": {"1.1.1.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []namedPrefix{
+		{"AU", netip.MustParsePrefix("1.1.1.0/24")},
+		{"CN", netip.MustParsePrefix("1.0.1.0/24")},
+		{"CN", netip.MustParsePrefix("1.0.2.0/23")},
+		{"CN", netip.MustParsePrefix("240e::/20")},
+		{"US", netip.MustParsePrefix("3.0.0.0/8")},
+		{"US", netip.MustParsePrefix("8.8.8.0/24")},
+		{"US", netip.MustParsePrefix("2001:db8::/32")},
+	}
+
+	var got []namedPrefix
+	for name, prefix := range c.IterPrefixes() {
+		got = append(got, namedPrefix{name, prefix})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("IterPrefixes = %v, want %v", got, want)
+	}
+
+	var fromForEach []namedPrefix
+	err = c.ForEachPrefix(func(name string, prefix netip.Prefix) error {
+		fromForEach = append(fromForEach, namedPrefix{name, prefix})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, fromForEach) {
+		t.Errorf("IterPrefixes = %v, ForEachPrefix = %v, want the same order", got, fromForEach)
+	}
+
+	// Stopping early yields a prefix of the full order
+	var first []namedPrefix
+	for name, prefix := range c.IterPrefixes() {
+		first = append(first, namedPrefix{name, prefix})
+		if len(first) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(first, want[:3]) {
+		t.Errorf("IterPrefixes stopped after 3 = %v, want %v", first, want[:3])
+	}
+}
+
+func TestContainerGroupByPrefix(t *testing.T) {
+	c, err := NewContainerFromMap(map[string][]string{
+		"US": {"3.0.0.0/8", "8.8.8.0/24"},
+		"CN": {"1.0.1.0/24", "1.0.2.0/23", "2.0.0.0/24"},
+		"AU": {"1.1.1.0/24"},
+		"JP": {"4.0.0.0/24", "5.0.0.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := c.GroupByPrefix(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are never split, and ties go to the lowest block
+	want := map[string][]string{
+		"1.0.0.0/8": {"AU", "CN"},
+		"3.0.0.0/8": {"US"},
+		"4.0.0.0/8": {"JP"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for key, names := range want {
+		group, found := groups[key]
+		if !found {
+			t.Fatalf("group %s not found", key)
+		}
+		if got := group.(*container).names(); !slices.Equal(got, names) {
+			t.Errorf("group %s has entries %v, want %v", key, got, names)
+		}
+		for _, name := range names {
+			entry, _ := group.GetEntry(name)
+			original, _ := c.GetEntry(name)
+			got, _ := entry.MarshalText()
+			wantPrefixes, _ := original.MarshalText()
+			if !slices.Equal(got, wantPrefixes) {
+				t.Errorf("entry %s in group %s has %v, want %v", name, key, got, wantPrefixes)
+			}
+		}
+	}
+}
+
+func TestContainerPrune(t *testing.T) {
+	c, err := NewContainerFromMap(map[string][]string{
+		"US": {"8.8.8.0/24"},
+		"CN": {"1.0.1.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Prune([]string{"cn", "jp"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.(*container).names(); !slices.Equal(got, []string{"CN"}) {
+		t.Errorf("entries after Prune = %v, want [CN]", got)
+	}
+
+	if err := c.Prune(nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len after Prune(nil) = %d, want 0", c.Len())
+	}
+}
+
+func TestContainerSwapEntries(t *testing.T) {
+	c, err := NewContainerFromMap(map[string][]string{
+		"HK": {"1.0.1.0/24"},
+		"MO": {"2.0.1.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hk, _ := c.GetEntry("HK")
+	mo, _ := c.GetEntry("MO")
+	hkModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hk.SetLastModified(hkModified)
+	if err := hk.AddSourcedPrefixes(parsePrefixes(t, []string{"1.0.2.0/24"}), "hk.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SwapEntries("hk", "mo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The entries stay in place and only their prefixes move
+	if got, _ := c.GetEntry("HK"); got != hk || hk.GetName() != "HK" {
+		t.Fatalf("entry HK was replaced or renamed to %s", hk.GetName())
+	}
+	if got, _ := hk.MarshalText(); !slices.Equal(got, []string{"2.0.1.0/24"}) {
+		t.Errorf("HK has %v after SwapEntries, want [2.0.1.0/24]", got)
+	}
+	if got, _ := mo.MarshalText(); !slices.Equal(got, []string{"1.0.1.0/24", "1.0.2.0/24"}) {
+		t.Errorf("MO has %v after SwapEntries, want [1.0.1.0/24 1.0.2.0/24]", got)
+	}
+	if !hk.GetLastModified().Equal(hkModified) || !mo.GetLastModified().IsZero() {
+		t.Errorf("lastModified moved with the prefixes")
+	}
+	if hk.GetPrefixSource(netip.MustParsePrefix("1.0.2.0/24")) != "hk.txt" || len(mo.GetPrefixSources()) != 0 {
+		t.Errorf("sources moved with the prefixes")
+	}
+}
@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CSVOption changes how ImportFromCSV reads the CSV rows.
+type CSVOption func() (columnBase int)
+
+// CSVColumnBase sets the number of the first CSV column, which is 0 by
+// default. Use CSVColumnBase(1) for 1-indexed column numbers.
+func CSVColumnBase(base int) CSVOption {
+	return func() int {
+		return base
+	}
+}
+
+// ImportFromCSV adds the networks of the CSV rows read from r to the
+// container. networkCol and countryCol are the column numbers of the IP
+// address or CIDR and of the entry name, 0-indexed unless changed with
+// CSVColumnBase. The first row is skipped if hasHeader is true, and rows
+// with an empty entry name are skipped.
+func (c *container) ImportFromCSV(r io.Reader, networkCol, countryCol int, hasHeader bool, opts ...CSVOption) error {
+	columnBase := 0
+	for _, opt := range opts {
+		if opt != nil {
+			columnBase = opt()
+		}
+	}
+	if columnBase != 0 && columnBase != 1 {
+		return fmt.Errorf("column base must be 0 or 1, got %d", columnBase)
+	}
+
+	networkCol, countryCol = networkCol-columnBase, countryCol-columnBase
+	if networkCol < 0 || countryCol < 0 {
+		return fmt.Errorf("column numbers must not be less than %d", columnBase)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	if hasHeader {
+		if _, err := reader.Read(); err != nil {
+			return err
+		}
+	}
+
+	entries := make(map[string]*Entry)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if networkCol >= len(record) || countryCol >= len(record) {
+			return fmt.Errorf("line %d: expected at least %d columns, got %d", line, max(networkCol, countryCol)+1, len(record))
+		}
+
+		name := strings.ToUpper(strings.TrimSpace(record[countryCol]))
+		if name == "" {
+			continue
+		}
+
+		entry, found := entries[name]
+		if !found {
+			entry = NewEntry(name)
+			entries[name] = entry
+		}
+
+		if err := entry.AddPrefix(strings.TrimSpace(record[networkCol])); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if err := c.Add(entries[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
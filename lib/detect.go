@@ -0,0 +1,136 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// mmdbMetadataMarker starts the metadata section at the end of every MMDB file.
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+const mmdbMetadataMaxSize = 128 * 1024
+
+var inputFormatExtensions = map[string]string{
+	".mmdb": "maxmindMMDB",
+	".dat":  "v2rayGeoIPDat",
+	".txt":  "text",
+}
+
+// csvHeaderFormats maps the start of the header line of a CSV file to its
+// input format, as CSV files of different formats share the extension.
+var csvHeaderFormats = map[string]string{
+	"network,geoname_id,registered_country_geoname_id": "maxmindGeoLite2CountryCSV",
+}
+
+// DetectInputFormat returns the name of the input format of uri, using the
+// magic bytes of local files and falling back to the file extension. CSV
+// files are detected by their header line, so remote CSV files are never
+// detected.
+func DetectInputFormat(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	ext := ""
+
+	switch {
+	case strings.HasPrefix(strings.ToLower(uri), "http://"), strings.HasPrefix(strings.ToLower(uri), "https://"):
+		u, err := url.Parse(uri)
+		if err != nil {
+			return "", err
+		}
+		ext = strings.ToLower(path.Ext(u.Path))
+
+	default:
+		ext = strings.ToLower(filepath.Ext(uri))
+
+		file, err := os.Open(uri)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return "", err
+		}
+		if info.IsDir() {
+			return "text", nil
+		}
+
+		if offset := info.Size() - mmdbMetadataMaxSize; offset > 0 {
+			if _, err := file.Seek(offset, io.SeekStart); err != nil {
+				return "", err
+			}
+		}
+		tail, err := io.ReadAll(file)
+		if err != nil {
+			return "", err
+		}
+		if bytes.Contains(tail, mmdbMetadataMarker) {
+			return "maxmindMMDB", nil
+		}
+
+		if ext == ".dat" {
+			head := make([]byte, 1)
+			if _, err := file.ReadAt(head, 0); err != nil {
+				return "", err
+			}
+			// A GeoIPList message starts with the tag of its repeated entry field
+			if head[0] != 0x0a {
+				return "", fmt.Errorf("file %s is not a V2Ray GeoIP dat file", uri)
+			}
+		}
+
+		if ext == ".csv" {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return "", err
+			}
+			header, err := bufio.NewReader(file).ReadString('\n')
+			if err != nil && err != io.EOF {
+				return "", err
+			}
+			header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+			for start, format := range csvHeaderFormats {
+				if strings.HasPrefix(header, start) {
+					return format, nil
+				}
+			}
+		}
+	}
+
+	if format, found := inputFormatExtensions[ext]; found {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("cannot detect input format of %s, please specify --input explicitly", uri)
+}
+
+var outputFormatExtensions = map[string][]string{
+	".csv":  {"splunkLookup"},
+	".dat":  {"v2rayGeoIPDat"},
+	".html": {"htmlReport"},
+	".json": {"cloudflareKV", "containerManifest"},
+	".txt":  {"text"},
+}
+
+// DetectOutputFormat returns the name of the output format for filename
+// based on its extension. An error is returned when the extension is unknown
+// or shared by several output formats.
+func DetectOutputFormat(filename string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSpace(filename)))
+
+	formats := outputFormatExtensions[ext]
+	switch len(formats) {
+	case 0:
+		return "", fmt.Errorf("cannot detect output format of %s, please specify --output explicitly", filename)
+	case 1:
+		return formats[0], nil
+	default:
+		return "", fmt.Errorf("extension %s of %s is used by output formats %s, please specify --output explicitly", ext, filename, strings.Join(formats, ", "))
+	}
+}
@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"net/netip"
+	"slices"
+
+	"go4.org/netipx"
+)
+
+// DiffResult describes the changes needed to turn one container into another.
+type DiffResult struct {
+	Added    []string
+	Removed  []string
+	Modified []EntryDiff
+}
+
+// EntryDiff describes the changes of an entry present in both containers.
+type EntryDiff struct {
+	Name            string
+	AddedPrefixes   []netip.Prefix
+	RemovedPrefixes []netip.Prefix
+}
+
+func (c *container) Diff(other Container) (DiffResult, error) {
+	return ContainerDiff(c, other)
+}
+
+// ContainerDiff compares two containers and returns the entries which were
+// added to b, removed from a, and modified between a and b.
+func ContainerDiff(a, b Container) (DiffResult, error) {
+	var diff DiffResult
+
+	for _, name := range sortedNames(b) {
+		if _, found := a.GetEntry(name); !found {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+
+	for _, name := range sortedNames(a) {
+		entry, _ := a.GetEntry(name)
+		otherEntry, found := b.GetEntry(name)
+		if !found {
+			diff.Removed = append(diff.Removed, name)
+			continue
+		}
+
+		set, err := entry.ipSet()
+		if err != nil {
+			return DiffResult{}, err
+		}
+		otherSet, err := otherEntry.ipSet()
+		if err != nil {
+			return DiffResult{}, err
+		}
+		if set.Equal(otherSet) {
+			continue
+		}
+
+		added, err := subtractIPSet(otherSet, set)
+		if err != nil {
+			return DiffResult{}, err
+		}
+		removed, err := subtractIPSet(set, otherSet)
+		if err != nil {
+			return DiffResult{}, err
+		}
+
+		diff.Modified = append(diff.Modified, EntryDiff{
+			Name:            name,
+			AddedPrefixes:   added.Prefixes(),
+			RemovedPrefixes: removed.Prefixes(),
+		})
+	}
+
+	return diff, nil
+}
+
+// sortedNames returns the entry names of c in sorted order.
+func sortedNames(c Container) []string {
+	names := make([]string, 0, c.Len())
+	for entry := range c.Loop() {
+		names = append(names, entry.GetName())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// subtractIPSet returns the addresses of a which are not in b.
+func subtractIPSet(a, b *netipx.IPSet) (*netipx.IPSet, error) {
+	var builder netipx.IPSetBuilder
+	builder.AddSet(a)
+	builder.RemoveSet(b)
+	return builder.IPSet()
+}
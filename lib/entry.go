@@ -2,9 +2,15 @@ package lib
 
 import (
 	"fmt"
+	"iter"
+	"maps"
+	"math/big"
 	"net"
 	"net/netip"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"go4.org/netipx"
 )
@@ -15,6 +21,10 @@ type Entry struct {
 	ipv6Builder *netipx.IPSetBuilder
 	ipv4Set     *netipx.IPSet
 	ipv6Set     *netipx.IPSet
+
+	lastModified time.Time
+	sources      map[netip.Prefix]string
+	priorities   map[netip.Prefix]map[string]int
 }
 
 func NewEntry(name string) *Entry {
@@ -27,6 +37,57 @@ func (e *Entry) GetName() string {
 	return e.name
 }
 
+// SetName changes the name of the entry. The name must be valid as
+// described by validEntryName.
+func (e *Entry) SetName(name string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !validEntryName(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidEntryName, name)
+	}
+	e.name = name
+	return nil
+}
+
+// validEntryName reports whether name is a valid entry name. A name is made
+// of one or more segments separated by "/", where all segments but the last
+// are namespaces, e.g. "MYTENANT/CN". No segment may be empty or contain
+// whitespace or any of the characters \:*?"<>|.
+func validEntryName(name string) bool {
+	for segment := range strings.SplitSeq(name, "/") {
+		if segment == "" || strings.ContainsFunc(segment, unicode.IsSpace) || strings.ContainsAny(segment, `\:*?"<>|`) {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of the entry which shares no builders with e.
+func (e *Entry) clone() *Entry {
+	c := &Entry{
+		name:         e.name,
+		ipv4Set:      e.ipv4Set,
+		ipv6Set:      e.ipv6Set,
+		lastModified: e.lastModified,
+		sources:      maps.Clone(e.sources),
+		priorities:   clonePriorities(e.priorities),
+	}
+	if e.hasIPv4Builder() {
+		c.ipv4Builder = e.ipv4Builder.Clone()
+	}
+	if e.hasIPv6Builder() {
+		c.ipv6Builder = e.ipv6Builder.Clone()
+	}
+	return c
+}
+
+func (e *Entry) GetLastModified() time.Time {
+	return e.lastModified
+}
+
+func (e *Entry) SetLastModified(t time.Time) {
+	e.lastModified = t
+}
+
 func (e *Entry) hasIPv4Builder() bool {
 	return e.ipv4Builder != nil
 }
@@ -43,6 +104,13 @@ func (e *Entry) hasIPv6Set() bool {
 	return e.ipv6Set != nil
 }
 
+// resetIPSet drops the cached IP sets so that they are rebuilt
+// from the builders the next time they are needed.
+func (e *Entry) resetIPSet() {
+	e.ipv4Set = nil
+	e.ipv6Set = nil
+}
+
 func (e *Entry) GetIPv4Set() (*netipx.IPSet, error) {
 	if err := e.buildIPSet(); err != nil {
 		return nil, err
@@ -67,6 +135,27 @@ func (e *Entry) GetIPv6Set() (*netipx.IPSet, error) {
 	return nil, fmt.Errorf("entry %s has no ipv6 set", e.GetName())
 }
 
+// GetIPVersion returns IPv4 or IPv6 if the entry only holds addresses of
+// that type, or IPBoth if it holds both.
+func (e *Entry) GetIPVersion() (IPType, error) {
+	if err := e.buildIPSet(); err != nil {
+		return "", err
+	}
+
+	hasIPv4 := e.hasIPv4Set() && len(e.ipv4Set.Ranges()) > 0
+	hasIPv6 := e.hasIPv6Set() && len(e.ipv6Set.Ranges()) > 0
+	switch {
+	case hasIPv4 && hasIPv6:
+		return IPBoth, nil
+	case hasIPv4:
+		return IPv4, nil
+	case hasIPv6:
+		return IPv6, nil
+	default:
+		return "", fmt.Errorf("entry %s has no prefix", e.GetName())
+	}
+}
+
 func (e *Entry) processPrefix(src any) (*netip.Prefix, IPType, error) {
 	switch src := src.(type) {
 	case net.IP:
@@ -198,6 +287,10 @@ func (e *Entry) processPrefix(src any) (*netip.Prefix, IPType, error) {
 
 		switch strings.Contains(src, "/") {
 		case true: // src is CIDR notation
+			if prefix, err := netip.ParsePrefix(src); err == nil && prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+				prefix = unmapPrefix(prefix.Masked())
+				return &prefix, IPv4, nil
+			}
 			ip, network, err := net.ParseCIDR(src)
 			if err != nil {
 				return nil, "", ErrInvalidCIDR
@@ -247,6 +340,8 @@ func (e *Entry) processPrefix(src any) (*netip.Prefix, IPType, error) {
 }
 
 func (e *Entry) add(prefix *netip.Prefix, ipType IPType) error {
+	e.resetIPSet()
+
 	switch ipType {
 	case IPv4:
 		if !e.hasIPv4Builder() {
@@ -266,6 +361,8 @@ func (e *Entry) add(prefix *netip.Prefix, ipType IPType) error {
 }
 
 func (e *Entry) remove(prefix *netip.Prefix, ipType IPType) error {
+	e.resetIPSet()
+
 	switch ipType {
 	case IPv4:
 		if e.hasIPv4Builder() {
@@ -293,6 +390,212 @@ func (e *Entry) AddPrefix(cidr any) error {
 	return nil
 }
 
+// AddCIDRString parses cidr as a prefix and adds it to the entry.
+func (e *Entry) AddCIDRString(cidr string) error {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidCIDR, cidr, err)
+	}
+	return e.AddPrefix(prefix)
+}
+
+// AddCIDRStrings adds each of cidrs like AddCIDRString, without stopping at
+// invalid ones. The returned slice has the error of each CIDR, nil for the
+// CIDRs added.
+func (e *Entry) AddCIDRStrings(cidrs []string) []error {
+	errs := make([]error, len(cidrs))
+	for i, cidr := range cidrs {
+		errs[i] = e.AddCIDRString(cidr)
+	}
+	return errs
+}
+
+// AddIPNet adds ipNet to the entry, for callers still using net.IPNet.
+func (e *Entry) AddIPNet(ipNet *net.IPNet) error {
+	if ipNet == nil {
+		return ErrInvalidIPNet
+	}
+
+	addr, ok := netip.AddrFromSlice(ipNet.IP)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidIP, ipNet.IP)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits == 0 {
+		return fmt.Errorf("%w: invalid mask %s", ErrInvalidIPNet, ipNet.Mask)
+	}
+	if bits == 32 {
+		addr = addr.Unmap()
+	}
+
+	prefix := netip.PrefixFrom(addr, ones)
+	if !prefix.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidIPNet, ipNet)
+	}
+
+	return e.AddPrefix(prefix)
+}
+
+// AddSourcedPrefixes adds prefixes to the entry and records source as the
+// origin of each of them. The sources are only kept for debugging and are
+// not used by output converters.
+func (e *Entry) AddSourcedPrefixes(prefixes []netip.Prefix, source string) error {
+	for _, prefix := range prefixes {
+		p, ipType, err := e.processPrefix(prefix)
+		if err != nil {
+			return err
+		}
+		if err := e.add(p, ipType); err != nil {
+			return err
+		}
+
+		if e.sources == nil {
+			e.sources = make(map[netip.Prefix]string)
+		}
+		e.sources[*p] = source
+	}
+
+	return nil
+}
+
+// AddPrefixWithPriority adds prefix to the entry and records source as its
+// origin with the given priority. When the same prefix is added by several
+// sources, ResolveConflicts keeps the source with the highest priority.
+func (e *Entry) AddPrefixWithPriority(prefix netip.Prefix, source string, priority int) error {
+	p, ipType, err := e.processPrefix(prefix)
+	if err != nil {
+		return err
+	}
+	if err := e.add(p, ipType); err != nil {
+		return err
+	}
+
+	if e.sources == nil {
+		e.sources = make(map[netip.Prefix]string)
+	}
+	e.sources[*p] = source
+	e.addPriority(*p, source, priority)
+
+	return nil
+}
+
+// addPriority records the priority of source for prefix, keeping the
+// highest one if source is recorded already.
+func (e *Entry) addPriority(prefix netip.Prefix, source string, priority int) {
+	if e.priorities == nil {
+		e.priorities = make(map[netip.Prefix]map[string]int)
+	}
+	if e.priorities[prefix] == nil {
+		e.priorities[prefix] = make(map[string]int)
+	}
+	if current, found := e.priorities[prefix][source]; !found || priority > current {
+		e.priorities[prefix][source] = priority
+	}
+}
+
+// ResolveConflicts keeps only the source with the highest priority for each
+// prefix added by several sources with AddPrefixWithPriority, and returns the
+// number of lower-priority sources dropped. Ties are broken by source name.
+func (e *Entry) ResolveConflicts() int {
+	removed := 0
+	for prefix, priorities := range e.priorities {
+		if len(priorities) < 2 {
+			continue
+		}
+
+		winner := ""
+		for _, source := range slices.Sorted(maps.Keys(priorities)) {
+			if winner == "" || priorities[source] > priorities[winner] {
+				winner = source
+			}
+		}
+
+		removed += len(priorities) - 1
+		e.priorities[prefix] = map[string]int{winner: priorities[winner]}
+		e.sources[prefix] = winner
+	}
+
+	return removed
+}
+
+// GetPrefixSource returns the source recorded for prefix,
+// or an empty string if none is recorded.
+func (e *Entry) GetPrefixSource(prefix netip.Prefix) string {
+	return e.sources[prefix]
+}
+
+// GetPrefixSources returns all recorded prefixes and their sources.
+func (e *Entry) GetPrefixSources() map[netip.Prefix]string {
+	return maps.Clone(e.sources)
+}
+
+// mergeSources copies the recorded sources of other into e, skipping the
+// prefixes of ignoreIPType.
+func (e *Entry) mergeSources(other *Entry, ignoreIPType IPType) {
+	for prefix, source := range other.sources {
+		if isIgnoredPrefix(prefix, ignoreIPType) {
+			continue
+		}
+		if e.sources == nil {
+			e.sources = make(map[netip.Prefix]string)
+		}
+		e.sources[prefix] = source
+	}
+
+	for prefix, priorities := range other.priorities {
+		if isIgnoredPrefix(prefix, ignoreIPType) {
+			continue
+		}
+		for source, priority := range priorities {
+			e.addPriority(prefix, source, priority)
+		}
+	}
+}
+
+func clonePriorities(priorities map[netip.Prefix]map[string]int) map[netip.Prefix]map[string]int {
+	if priorities == nil {
+		return nil
+	}
+	cloned := make(map[netip.Prefix]map[string]int, len(priorities))
+	for prefix, sources := range priorities {
+		cloned[prefix] = maps.Clone(sources)
+	}
+	return cloned
+}
+
+// sourcedPrefixes returns the prefixes recorded with a source, in address
+// order, skipping those which are no longer fully covered by the IP sets of
+// the entry. Unlike the IP sets, they keep the prefixes as they were added,
+// so they may overlap each other.
+func (e *Entry) sourcedPrefixes() ([]netip.Prefix, error) {
+	if err := e.buildIPSet(); err != nil {
+		return nil, err
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(e.sources))
+	for prefix := range e.sources {
+		switch {
+		case prefix.Addr().Is4() && e.hasIPv4Set() && e.ipv4Set.ContainsPrefix(prefix),
+			prefix.Addr().Is6() && e.hasIPv6Set() && e.ipv6Set.ContainsPrefix(prefix):
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	slices.SortFunc(prefixes, comparePrefix)
+	return prefixes, nil
+}
+
+func isIgnoredPrefix(prefix netip.Prefix, ignoreIPType IPType) bool {
+	switch ignoreIPType {
+	case IPv4:
+		return prefix.Addr().Is4()
+	case IPv6:
+		return prefix.Addr().Is6()
+	default:
+		return false
+	}
+}
+
 func (e *Entry) RemovePrefix(cidr string) error {
 	prefix, ipType, err := e.processPrefix(cidr)
 	if err != nil && err != ErrCommentLine {
@@ -324,6 +627,396 @@ func (e *Entry) buildIPSet() error {
 	return nil
 }
 
+// ipSet returns a single set holding both the IPv4 and IPv6 addresses of the entry.
+func (e *Entry) ipSet() (*netipx.IPSet, error) {
+	if err := e.buildIPSet(); err != nil {
+		return nil, err
+	}
+
+	var builder netipx.IPSetBuilder
+	if e.hasIPv4Set() {
+		builder.AddSet(e.ipv4Set)
+	}
+	if e.hasIPv6Set() {
+		builder.AddSet(e.ipv6Set)
+	}
+
+	return builder.IPSet()
+}
+
+// Intersection returns a new entry with the name of e, holding the addresses
+// covered by both e and other.
+func (e *Entry) Intersection(other *Entry) (*Entry, error) {
+	set, err := e.ipSet()
+	if err != nil {
+		return nil, err
+	}
+	otherSet, err := other.ipSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var builder netipx.IPSetBuilder
+	builder.AddSet(set)
+	builder.Intersect(otherSet)
+	intersection, err := builder.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := NewEntry(e.GetName())
+	for _, prefix := range intersection.Prefixes() {
+		if err := entry.AddPrefix(prefix); err != nil {
+			return nil, err
+		}
+	}
+	entry.SetLastModified(e.GetLastModified())
+	if other.GetLastModified().After(e.GetLastModified()) {
+		entry.SetLastModified(other.GetLastModified())
+	}
+
+	return entry, nil
+}
+
+// Union returns a new entry holding the addresses covered by e or any of
+// others, named after all of them joined by _OR_, e.g. A_OR_B_OR_C.
+func (e *Entry) Union(others ...*Entry) (*Entry, error) {
+	var builder netipx.IPSetBuilder
+	names := make([]string, 0, len(others)+1)
+	lastModified := e.GetLastModified()
+	for _, entry := range append([]*Entry{e}, others...) {
+		set, err := entry.ipSet()
+		if err != nil {
+			return nil, err
+		}
+		builder.AddSet(set)
+		names = append(names, entry.GetName())
+		if entry.GetLastModified().After(lastModified) {
+			lastModified = entry.GetLastModified()
+		}
+	}
+	union, err := builder.IPSet()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := NewEntry(strings.Join(names, "_OR_"))
+	for _, prefix := range union.Prefixes() {
+		if err := entry.AddPrefix(prefix); err != nil {
+			return nil, err
+		}
+	}
+	entry.SetLastModified(lastModified)
+
+	return entry, nil
+}
+
+// ContainsEntry reports whether all addresses of other are covered by e.
+func (e *Entry) ContainsEntry(other *Entry) (bool, error) {
+	set, err := e.ipSet()
+	if err != nil {
+		return false, err
+	}
+	otherSet, err := other.ipSet()
+	if err != nil {
+		return false, err
+	}
+
+	var builder netipx.IPSetBuilder
+	builder.AddSet(otherSet)
+	builder.RemoveSet(set)
+	rest, err := builder.IPSet()
+	if err != nil {
+		return false, err
+	}
+
+	return len(rest.Ranges()) == 0, nil
+}
+
+// OverlapsWith reports whether e and other have any address in common.
+func (e *Entry) OverlapsWith(other *Entry) (bool, error) {
+	set, err := e.ipSet()
+	if err != nil {
+		return false, err
+	}
+	otherSet, err := other.ipSet()
+	if err != nil {
+		return false, err
+	}
+
+	return set.Overlaps(otherSet), nil
+}
+
+// GetSubnets returns the minimal prefixes of the entry which lie within
+// superPrefix, e.g. all prefixes of the entry inside 10.0.0.0/8.
+func (e *Entry) GetSubnets(superPrefix netip.Prefix) ([]netip.Prefix, error) {
+	if !superPrefix.IsValid() {
+		return nil, ErrInvalidPrefix
+	}
+	if err := e.buildIPSet(); err != nil {
+		return nil, err
+	}
+
+	superPrefix = superPrefix.Masked()
+	subnets := make([]netip.Prefix, 0)
+	for prefix := range e.Prefixes(IPBoth) {
+		if prefix.Bits() >= superPrefix.Bits() && superPrefix.Contains(prefix.Addr()) {
+			subnets = append(subnets, prefix)
+		}
+	}
+
+	return subnets, nil
+}
+
+// HasPrefix reports whether the whole prefix is covered by the entry. An
+// IPv4-mapped IPv6 prefix such as ::ffff:1.2.3.0/120 is checked as the IPv4
+// prefix 1.2.3.0/24, and must not be shorter than /96.
+func (e *Entry) HasPrefix(prefix netip.Prefix) (bool, error) {
+	if !prefix.IsValid() {
+		return false, ErrInvalidPrefix
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() < 96 {
+		return false, fmt.Errorf("%w: %s", ErrInvalidPrefix, prefix)
+	}
+	if err := e.buildIPSet(); err != nil {
+		return false, err
+	}
+
+	prefix = unmapPrefix(prefix.Masked())
+	switch {
+	case prefix.Addr().Is4():
+		return e.hasIPv4Set() && e.ipv4Set.ContainsPrefix(prefix), nil
+	default:
+		return e.hasIPv6Set() && e.ipv6Set.ContainsPrefix(prefix), nil
+	}
+}
+
+// CoveredBy reports whether all addresses of the entry are inside superPrefix.
+func (e *Entry) CoveredBy(superPrefix netip.Prefix) (bool, error) {
+	if !superPrefix.IsValid() {
+		return false, ErrInvalidPrefix
+	}
+	if err := e.buildIPSet(); err != nil {
+		return false, err
+	}
+
+	superPrefix = superPrefix.Masked()
+	for prefix := range e.Prefixes(IPBoth) {
+		if prefix.Bits() < superPrefix.Bits() || !superPrefix.Contains(prefix.Addr()) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// SplitByCIDRSize splits the minimal prefixes of the entry into coarse ones,
+// no longer than maxPrefixLen, and fine ones, longer than maxPrefixLen.
+// The two entries are named after e with the suffixes _COARSE and _FINE.
+func (e *Entry) SplitByCIDRSize(maxPrefixLen int) (coarse *Entry, fine *Entry, err error) {
+	if maxPrefixLen < 0 || maxPrefixLen > 128 {
+		return nil, nil, fmt.Errorf("invalid max prefix length %d", maxPrefixLen)
+	}
+
+	coarse = NewEntry(e.GetName() + "_COARSE")
+	fine = NewEntry(e.GetName() + "_FINE")
+	for prefix := range e.Prefixes(IPBoth) {
+		target := coarse
+		if prefix.Bits() > maxPrefixLen {
+			target = fine
+		}
+		if err := target.AddPrefix(prefix); err != nil {
+			return nil, nil, err
+		}
+	}
+	coarse.SetLastModified(e.GetLastModified())
+	fine.SetLastModified(e.GetLastModified())
+
+	return coarse, fine, nil
+}
+
+// removeSet removes all addresses in set from the entry.
+func (e *Entry) removeSet(set *netipx.IPSet) {
+	if e.hasIPv4Builder() {
+		e.ipv4Builder.RemoveSet(set)
+	}
+	if e.hasIPv6Builder() {
+		e.ipv6Builder.RemoveSet(set)
+	}
+	e.resetIPSet()
+}
+
+// Compact rebuilds the builders of the entry from its IP sets, which leaves
+// only the minimal set of prefixes and drops redundant subnets.
+func (e *Entry) Compact() error {
+	if err := e.buildIPSet(); err != nil {
+		return err
+	}
+
+	if e.hasIPv4Set() {
+		e.ipv4Builder = new(netipx.IPSetBuilder)
+		e.ipv4Builder.AddSet(e.ipv4Set)
+	}
+
+	if e.hasIPv6Set() {
+		e.ipv6Builder = new(netipx.IPSetBuilder)
+		e.ipv6Builder.AddSet(e.ipv6Set)
+	}
+
+	return nil
+}
+
+// Defragment compacts the entry like Compact, and returns the number of
+// prefixes recorded with a source (see AddSourcedPrefixes and
+// AddPrefixWithPriority) that were dropped because they are strict subnets
+// of another recorded prefix. Prefixes added without a source are merged
+// by the builders as they are added, so they are never counted.
+func (e *Entry) Defragment() (removed int, err error) {
+	prefixes, err := e.sourcedPrefixes()
+	if err != nil {
+		return 0, err
+	}
+
+	// Once sorted, a prefix is redundant if it is covered by the last
+	// prefix which was not redundant itself
+	var cover netip.Prefix
+	redundant := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if cover.IsValid() && cover.Bits() <= prefix.Bits() && cover.Contains(prefix.Addr()) {
+			redundant = append(redundant, prefix)
+			continue
+		}
+		cover = prefix
+	}
+
+	for _, prefix := range redundant {
+		delete(e.sources, prefix)
+		delete(e.priorities, prefix)
+	}
+	removed = len(redundant)
+
+	if err := e.Compact(); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// SortPrefixes re-inserts all prefixes of the entry into fresh builders, so
+// that the prefixes are kept in address order. The entry does not keep the
+// order in which prefixes were added, so there is nothing to compare the
+// sorted order with.
+func (e *Entry) SortPrefixes() error {
+	if err := e.buildIPSet(); err != nil {
+		return err
+	}
+
+	ipv4Builder, ipv6Builder := new(netipx.IPSetBuilder), new(netipx.IPSetBuilder)
+	if e.hasIPv4Set() {
+		for _, prefix := range e.ipv4Set.Prefixes() {
+			ipv4Builder.AddPrefix(prefix)
+		}
+	}
+	if e.hasIPv6Set() {
+		for _, prefix := range e.ipv6Set.Prefixes() {
+			ipv6Builder.AddPrefix(prefix)
+		}
+	}
+
+	ipv4Set, err := ipv4Builder.IPSet()
+	if err != nil {
+		return err
+	}
+	ipv6Set, err := ipv6Builder.IPSet()
+	if err != nil {
+		return err
+	}
+
+	if e.hasIPv4Set() {
+		e.ipv4Builder, e.ipv4Set = ipv4Builder, ipv4Set
+	}
+	if e.hasIPv6Set() {
+		e.ipv6Builder, e.ipv6Set = ipv6Builder, ipv6Set
+	}
+
+	return nil
+}
+
+var (
+	ipv4SpaceSize = new(big.Int).Lsh(big.NewInt(1), 32)
+	ipv6SpaceSize = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// IPv4Coverage returns the fraction of the whole IPv4 address space
+// covered by the entry, in the range [0, 1].
+func (e *Entry) IPv4Coverage() (float64, error) {
+	if err := e.buildIPSet(); err != nil {
+		return 0, err
+	}
+	if !e.hasIPv4Set() {
+		return 0, nil
+	}
+
+	coverage, _ := new(big.Float).Quo(new(big.Float).SetInt(countIPs(e.ipv4Set)), new(big.Float).SetInt(ipv4SpaceSize)).Float64()
+	return coverage, nil
+}
+
+// IPv6Coverage returns the fraction of the whole IPv6 address space
+// covered by the entry, in the range [0, 1].
+func (e *Entry) IPv6Coverage() (float64, error) {
+	coverage, err := e.IPv6CoveragePrecise()
+	if err != nil {
+		return 0, err
+	}
+
+	f, _ := coverage.Float64()
+	return f, nil
+}
+
+// IPv6CoveragePrecise works like IPv6Coverage, but keeps the full precision
+// of tiny fractions which may not fit in a float64.
+func (e *Entry) IPv6CoveragePrecise() (*big.Float, error) {
+	if err := e.buildIPSet(); err != nil {
+		return nil, err
+	}
+	if !e.hasIPv6Set() {
+		return new(big.Float), nil
+	}
+
+	count := new(big.Float).SetPrec(256).SetInt(countIPs(e.ipv6Set))
+	return count.Quo(count, new(big.Float).SetPrec(256).SetInt(ipv6SpaceSize)), nil
+}
+
+// Prefixes returns an iterator over the minimal prefixes of the entry of
+// ipType, or of both IP types for IPBoth. Nothing is yielded if the IP sets
+// of the entry cannot be built.
+func (e *Entry) Prefixes(ipType IPType) iter.Seq[netip.Prefix] {
+	return func(yield func(netip.Prefix) bool) {
+		if err := e.buildIPSet(); err != nil {
+			return
+		}
+
+		if ipType != IPv6 && e.hasIPv4Set() {
+			for _, prefix := range e.ipv4Set.Prefixes() {
+				if !yield(prefix) {
+					return
+				}
+			}
+		}
+
+		if ipType != IPv4 && e.hasIPv6Set() {
+			for _, prefix := range e.ipv6Set.Prefixes() {
+				if !yield(prefix) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MarshalPrefix returns the minimal prefixes of the entry, IPv4 first.
+// The IP sets are canonical, so the prefixes are always sorted by address
+// and do not depend on the order in which they were added.
 func (e *Entry) MarshalPrefix(opts ...IgnoreIPOption) ([]netip.Prefix, error) {
 	var ignoreIPType IPType
 	for _, opt := range opts {
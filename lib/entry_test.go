@@ -0,0 +1,224 @@
+package lib
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestEntryCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     []string
+	}{
+		{
+			name:     "nested",
+			prefixes: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"},
+			want:     []string{"10.0.0.0/8"},
+		},
+		{
+			name:     "adjacent",
+			prefixes: []string{"192.168.0.0/24", "192.168.1.0/24"},
+			want:     []string{"192.168.0.0/23"},
+		},
+		{
+			name:     "mixed families",
+			prefixes: []string{"2001:db8::/32", "2001:db8:1::/48", "1.1.1.0/24", "1.1.1.1/32"},
+			want:     []string{"1.1.1.0/24", "2001:db8::/32"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := NewEntry("TEST")
+			for _, prefix := range tt.prefixes {
+				if err := entry.AddPrefix(prefix); err != nil {
+					t.Fatalf("AddPrefix(%q): %v", prefix, err)
+				}
+			}
+
+			if err := entry.Compact(); err != nil {
+				t.Fatalf("Compact: %v", err)
+			}
+
+			got := slices.Collect(entry.Prefixes(IPv4))
+			got = slices.AppendSeq(got, entry.Prefixes(IPv6))
+			if !slices.Equal(got, parsePrefixes(t, tt.want)) {
+				t.Errorf("prefixes after Compact = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func parsePrefixes(t *testing.T, cidrs []string) []netip.Prefix {
+	t.Helper()
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefixes = append(prefixes, netip.MustParsePrefix(cidr))
+	}
+	return prefixes
+}
+
+func TestEntryDefragment(t *testing.T) {
+	entry := NewEntry("TEST")
+	cidrs := []string{"10.1.0.0/16", "10.0.0.0/8", "10.1.2.0/24", "192.168.0.0/24", "192.168.1.0/24", "2001:db8::/32", "2001:db8:1::/48"}
+	if err := entry.AddSourcedPrefixes(parsePrefixes(t, cidrs), "test"); err != nil {
+		t.Fatalf("AddSourcedPrefixes: %v", err)
+	}
+
+	report, err := entry.Audit()
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !report.HasOverlap {
+		t.Errorf("Audit.HasOverlap = false, want true")
+	}
+
+	removed, err := entry.Defragment()
+	if err != nil {
+		t.Fatalf("Defragment: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Defragment removed %d prefixes, want 3", removed)
+	}
+
+	// A second pass has nothing left to remove
+	if removed, err := entry.Defragment(); err != nil || removed != 0 {
+		t.Errorf("second Defragment = %d, %v, want 0, nil", removed, err)
+	}
+
+	// Merging an entry into itself must not record anything twice
+	container := NewContainer()
+	if err := container.Add(entry); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := container.Add(entry); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if removed, err := entry.Defragment(); err != nil || removed != 0 {
+		t.Errorf("Defragment after self-merge = %d, %v, want 0, nil", removed, err)
+	}
+}
+
+func TestEntryDefragmentUnsourced(t *testing.T) {
+	entry := NewEntry("TEST")
+	for _, prefix := range []string{"10.0.0.0/8", "10.1.0.0/16"} {
+		if err := entry.AddPrefix(prefix); err != nil {
+			t.Fatalf("AddPrefix(%q): %v", prefix, err)
+		}
+	}
+
+	// Prefixes without a source are not recorded, so none is counted
+	if removed, err := entry.Defragment(); err != nil || removed != 0 {
+		t.Errorf("Defragment = %d, %v, want 0, nil", removed, err)
+	}
+	if got := slices.Collect(entry.Prefixes(IPBoth)); !slices.Equal(got, parsePrefixes(t, []string{"10.0.0.0/8"})) {
+		t.Errorf("prefixes after Defragment = %v, want [10.0.0.0/8]", got)
+	}
+}
+
+func TestEntryMarshalPrefixOrder(t *testing.T) {
+	cidrs := []string{"192.168.0.0/16", "2001:db8::/32", "10.0.0.0/8", "1.1.1.1/32", "::1/128", "172.16.0.0/12", "fc00::/7"}
+	want := parsePrefixes(t, []string{"1.1.1.1/32", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "::1/128", "2001:db8::/32", "fc00::/7"})
+
+	// Insert the same prefixes in every rotation of cidrs, and in reverse
+	var orders [][]string
+	for i := range cidrs {
+		orders = append(orders, append(slices.Clone(cidrs[i:]), cidrs[:i]...))
+	}
+	reversed := slices.Clone(cidrs)
+	slices.Reverse(reversed)
+	orders = append(orders, reversed)
+
+	for _, order := range orders {
+		entry := NewEntry("TEST")
+		for _, cidr := range order {
+			if err := entry.AddPrefix(cidr); err != nil {
+				t.Fatalf("AddPrefix(%q): %v", cidr, err)
+			}
+		}
+
+		got, err := entry.MarshalPrefix()
+		if err != nil {
+			t.Fatalf("MarshalPrefix: %v", err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("MarshalPrefix after adding %v = %v, want %v", order, got, want)
+		}
+
+		set, err := entry.ipSet()
+		if err != nil {
+			t.Fatalf("ipSet: %v", err)
+		}
+		if got := set.Prefixes(); !slices.Equal(got, want) {
+			t.Errorf("IPSet.Prefixes after adding %v = %v, want %v", order, got, want)
+		}
+	}
+}
+
+func TestEntrySetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "cn", valid: true},
+		{name: "mytenant/cn", valid: true},
+		{name: "a/b/cn", valid: true},
+		{name: "", valid: false},
+		{name: "/cn", valid: false},
+		{name: "mytenant/", valid: false},
+		{name: "a//cn", valid: false},
+		{name: "my tenant/cn", valid: false},
+		{name: `a\cn`, valid: false},
+		{name: "cn:1", valid: false},
+	}
+
+	for _, tt := range tests {
+		entry := NewEntry("OLD")
+		err := entry.SetName(tt.name)
+		if tt.valid != (err == nil) {
+			t.Errorf("SetName(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+
+		container := NewContainer()
+		if err := container.Add(NewEntry("OLD")); err != nil {
+			t.Fatal(err)
+		}
+		err = container.RenameEntry("OLD", tt.name, false)
+		if tt.valid != (err == nil) {
+			t.Errorf("RenameEntry(OLD, %q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestEntryHasPrefix(t *testing.T) {
+	entry := NewEntry("TEST")
+	for _, prefix := range []string{"1.2.3.0/24", "2001:db8::/32"} {
+		if err := entry.AddPrefix(prefix); err != nil {
+			t.Fatalf("AddPrefix(%q): %v", prefix, err)
+		}
+	}
+
+	tests := []struct {
+		prefix  string
+		want    bool
+		wantErr bool
+	}{
+		{prefix: "1.2.3.0/24", want: true},
+		{prefix: "1.2.3.128/25", want: true},
+		{prefix: "1.2.2.0/23", want: false},
+		{prefix: "::ffff:1.2.3.0/120", want: true},
+		{prefix: "::ffff:1.2.3.128/121", want: true},
+		{prefix: "::ffff:1.2.2.0/119", want: false},
+		{prefix: "::ffff:1.2.3.0/80", wantErr: true},
+		{prefix: "2001:db8:1::/48", want: true},
+	}
+
+	for _, tt := range tests {
+		got, err := entry.HasPrefix(netip.MustParsePrefix(tt.prefix))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("HasPrefix(%s) = %v, %v, want %v, error %v", tt.prefix, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
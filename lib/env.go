@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ParseEnvFile reads a .env file with one KEY=value pair per line.
+// Empty lines and lines starting with # are skipped, an optional "export "
+// before the key is ignored, and matching quotes around the value are removed.
+func ParseEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid line %d in env file %s", lineNum, path)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// expandEnv replaces every ${VAR} in content with the value of VAR in vars,
+// or in the environment if vars does not have it. ${VAR} is kept as it is if
+// VAR is set in neither of them.
+func expandEnv(content []byte, vars map[string]string) []byte {
+	return envVarPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		name := string(envVarPattern.FindSubmatch(match)[1])
+		if value, found := vars[name]; found {
+			return []byte(value)
+		}
+		if value, found := os.LookupEnv(name); found {
+			return []byte(value)
+		}
+		return match
+	})
+}
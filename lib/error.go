@@ -3,14 +3,21 @@ package lib
 import "errors"
 
 var (
-	ErrDuplicatedConverter = errors.New("duplicated converter")
-	ErrUnknownAction       = errors.New("unknown action")
-	ErrInvalidIPType       = errors.New("invalid IP type")
-	ErrInvalidIP           = errors.New("invalid IP address")
-	ErrInvalidIPLength     = errors.New("invalid IP address length")
-	ErrInvalidIPNet        = errors.New("invalid IPNet address")
-	ErrInvalidCIDR         = errors.New("invalid CIDR")
-	ErrInvalidPrefix       = errors.New("invalid prefix")
-	ErrInvalidPrefixType   = errors.New("invalid prefix type")
-	ErrCommentLine         = errors.New("comment line")
+	ErrDuplicatedConverter   = errors.New("duplicated converter")
+	ErrDuplicatedCodec       = errors.New("duplicated codec")
+	ErrUnknownCodec          = errors.New("unknown codec")
+	ErrDuplicatedTransformer = errors.New("duplicated transformer")
+	ErrUnknownTransformer    = errors.New("unknown transformer")
+	ErrUnknownAction         = errors.New("unknown action")
+	ErrEntryNotFound         = errors.New("entry not found")
+	ErrEntryAlreadyExists    = errors.New("entry already exists")
+	ErrInvalidEntryName      = errors.New("invalid entry name")
+	ErrInvalidIPType         = errors.New("invalid IP type")
+	ErrInvalidIP             = errors.New("invalid IP address")
+	ErrInvalidIPLength       = errors.New("invalid IP address length")
+	ErrInvalidIPNet          = errors.New("invalid IPNet address")
+	ErrInvalidCIDR           = errors.New("invalid CIDR")
+	ErrInvalidPrefix         = errors.New("invalid prefix")
+	ErrInvalidPrefixType     = errors.New("invalid prefix type")
+	ErrCommentLine           = errors.New("comment line")
 )
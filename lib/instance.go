@@ -1,10 +1,19 @@
 package lib
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/tailscale/hujson"
 )
@@ -12,18 +21,29 @@ import (
 type Instance interface {
 	InitConfig(configFile string) error
 	InitConfigFromBytes(content []byte) error
+	InitConfigFromReader(r io.Reader) error
+	LoadEnvFile(path string) error
 	AddInput(InputConverter)
 	AddOutput(OutputConverter)
 	ResetInput()
 	ResetOutput()
 	RunInput(Container) error
 	RunOutput(Container) error
+	RunInputs() (Container, error)
+	RunOutputs(Container) error
 	Run() error
+	RunWithCheckpoint(checkpointPath string) error
+	RunBatch(configs []string) (errs []error, fatalErr error)
 }
 
 type instance struct {
-	input  []InputConverter
-	output []OutputConverter
+	input           []InputConverter
+	output          []OutputConverter
+	configFile      string
+	parallelOutputs bool
+	entryRenames    map[string]string
+	pushgateway     *pushgateway
+	envVars         map[string]string
 }
 
 func NewInstance() (Instance, error) {
@@ -33,14 +53,41 @@ func NewInstance() (Instance, error) {
 	}, nil
 }
 
+// defaultConfigFile is the config file used when no config file is given and
+// GEOIP_CONFIG and GEOIP_CONFIG_FILE are not set.
+const defaultConfigFile = "config.json"
+
+// InitConfig loads the config from configFile, which can be a local path,
+// a remote URL or - for stdin. If configFile is empty, the config is built
+// from the GEOIP_INPUTS and GEOIP_OUTPUTS environment variables, or else read
+// from GEOIP_CONFIG, or else from the file named by GEOIP_CONFIG_FILE, or else
+// from config.json.
 func (i *instance) InitConfig(configFile string) error {
 	var content []byte
 	var err error
 	configFile = strings.TrimSpace(configFile)
+	if configFile == "" {
+		if inputs := os.Getenv("GEOIP_INPUTS"); strings.TrimSpace(inputs) != "" {
+			content, err := envConfig(inputs, os.Getenv("GEOIP_OUTPUTS"))
+			if err != nil {
+				return err
+			}
+			return i.InitConfigFromBytes(content)
+		}
+		if content := os.Getenv("GEOIP_CONFIG"); strings.TrimSpace(content) != "" {
+			return i.InitConfigFromBytes([]byte(content))
+		}
+		configFile = cmp.Or(strings.TrimSpace(os.Getenv("GEOIP_CONFIG_FILE")), defaultConfigFile)
+	}
+	if configFile == "-" {
+		return i.InitConfigFromReader(os.Stdin)
+	}
+
 	if strings.HasPrefix(strings.ToLower(configFile), "http://") || strings.HasPrefix(strings.ToLower(configFile), "https://") {
 		content, err = GetRemoteURLContent(configFile)
 	} else {
 		content, err = os.ReadFile(configFile)
+		i.configFile = configFile
 	}
 	if err != nil {
 		return err
@@ -49,12 +96,55 @@ func (i *instance) InitConfig(configFile string) error {
 	return i.InitConfigFromBytes(content)
 }
 
+// envConfig builds a config from the JSON arrays of input and output
+// config objects in GEOIP_INPUTS and GEOIP_OUTPUTS.
+func envConfig(inputs, outputs string) ([]byte, error) {
+	var config struct {
+		Input  json.RawMessage `json:"input"`
+		Output json.RawMessage `json:"output,omitempty"`
+	}
+	config.Input = json.RawMessage(inputs)
+	if strings.TrimSpace(outputs) != "" {
+		config.Output = json.RawMessage(outputs)
+	}
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GEOIP_INPUTS or GEOIP_OUTPUTS: %w", err)
+	}
+	return content, nil
+}
+
 func (i *instance) InitConfigFromBytes(content []byte) error {
+	return i.InitConfigFromReader(bytes.NewReader(content))
+}
+
+func (i *instance) InitConfigFromReader(r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
 	config := new(config)
 
 	// Support JSON with comments and trailing commas
 	content, _ = hujson.Standardize(content)
 
+	// Load the env file of the config before expanding ${VAR}
+	var envConfig struct {
+		EnvFile string `json:"envFile"`
+	}
+	if json.Unmarshal(content, &envConfig) == nil && envConfig.EnvFile != "" {
+		if err := i.LoadEnvFile(envConfig.EnvFile); err != nil {
+			return err
+		}
+	}
+	// ${VAR} is only expanded when an env file is given, so that configs
+	// without one are read as they are
+	if i.envVars != nil {
+		content = expandEnv(content, i.envVars)
+	}
+
 	if err := json.Unmarshal(content, &config); err != nil {
 		return err
 	}
@@ -63,10 +153,40 @@ func (i *instance) InitConfigFromBytes(content []byte) error {
 		i.input = append(i.input, input.converter)
 	}
 
+	// Outputs run in the order of the output array unless outputOrder is set
+	config.sortOutput()
+
 	for _, output := range config.Output {
 		i.output = append(i.output, output.converter)
 	}
 
+	i.parallelOutputs = config.ParallelOutputs
+	i.entryRenames = config.EntryRenames
+
+	if gateway := strings.TrimSpace(config.PrometheusPushgateway); gateway != "" {
+		i.pushgateway = &pushgateway{
+			URL:           gateway,
+			JobName:       strings.TrimSpace(config.JobName),
+			InstanceLabel: strings.TrimSpace(config.InstanceLabel),
+		}
+	}
+
+	return nil
+}
+
+// LoadEnvFile loads the variables of a .env file for the ${VAR} expansion
+// of configs loaded afterwards. They take precedence over the environment.
+func (i *instance) LoadEnvFile(path string) error {
+	vars, err := ParseEnvFile(path)
+	if err != nil {
+		return err
+	}
+
+	if i.envVars == nil {
+		i.envVars = make(map[string]string, len(vars))
+	}
+	maps.Copy(i.envVars, vars)
+
 	return nil
 }
 
@@ -99,6 +219,10 @@ func (i *instance) RunInput(container Container) error {
 }
 
 func (i *instance) RunOutput(container Container) error {
+	if i.parallelOutputs {
+		return i.runOutputParallel(container)
+	}
+
 	for _, oc := range i.output {
 		if err := oc.Output(container); err != nil {
 			return err
@@ -108,20 +232,233 @@ func (i *instance) RunOutput(container Container) error {
 	return nil
 }
 
+// runOutputParallel runs all output converters concurrently and returns
+// all of their errors joined together.
+func (i *instance) runOutputParallel(container Container) error {
+	// Build the IP sets of all entries up front, so that the output
+	// converters only read from the entries
+	err := container.ForEachEntry(func(entry *Entry) error {
+		return entry.buildIPSet()
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, oc := range i.output {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := oc.Output(container); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("output %s: %w", oc.GetType(), err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// RunInputs runs all input converters against a new container and returns it,
+// so that the same container can be passed to RunOutputs multiple times.
+func (i *instance) RunInputs() (Container, error) {
+	if len(i.input) == 0 {
+		return nil, errors.New("input type must be specified")
+	}
+
+	container := NewContainer()
+
+	if err := i.RunInput(container); err != nil {
+		return nil, err
+	}
+
+	if err := i.renameEntries(container); err != nil {
+		return nil, err
+	}
+
+	return container, nil
+}
+
+// renameEntries applies entryRenames of the config to the container.
+// Renaming to an existing entry merges the two entries.
+func (i *instance) renameEntries(container Container) error {
+	for _, oldName := range slices.Sorted(maps.Keys(i.entryRenames)) {
+		newName := i.entryRenames[oldName]
+		err := container.RenameEntry(oldName, newName, true)
+		switch {
+		case errors.Is(err, ErrEntryNotFound):
+			log.Printf("⚠️ entry %s not found, skip renaming it to %s\n", oldName, newName)
+		case err != nil:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunOutputs runs all output converters against the given container.
+func (i *instance) RunOutputs(container Container) error {
+	if len(i.output) == 0 {
+		return errors.New("output type must be specified")
+	}
+	if container == nil {
+		return errors.New("container must not be nil")
+	}
+
+	return i.RunOutput(container)
+}
+
 func (i *instance) Run() error {
+	start := time.Now()
+	container, err := i.run()
+
+	if i.pushgateway != nil {
+		if pushErr := i.pushgateway.pushMetrics(container, time.Since(start), err == nil); pushErr != nil {
+			log.Printf("⚠️ %v", pushErr)
+		}
+	}
+
+	return err
+}
+
+func (i *instance) run() (Container, error) {
+	if len(i.input) == 0 || len(i.output) == 0 {
+		return nil, errors.New("input type and output type must be specified")
+	}
+
+	container, err := i.RunInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.RunOutputs(container); err != nil {
+		return container, err
+	}
+
+	logStats(container)
+
+	return container, nil
+}
+
+func logStats(container Container) {
+	ipv4Count, ipv6Count, err := container.TotalPrefixCount()
+	if err != nil {
+		return
+	}
+	ipCount, err := container.TotalIPCount()
+	if err != nil {
+		return
+	}
+	log.Printf("✅ %d entries, %d IPv4 prefixes, %d IPv6 prefixes, %s IPs", container.Len(), ipv4Count, ipv6Count, ipCount)
+}
+
+// RunBatch runs each config of configs in turn, resetting the instance
+// before loading each one. The returned errs has one element per config,
+// nil for the configs that ran successfully. fatalErr is only returned when
+// the batch cannot run at all.
+func (i *instance) RunBatch(configs []string) (errs []error, fatalErr error) {
+	if len(configs) == 0 {
+		return nil, errors.New("no config to run")
+	}
+
+	envVars := maps.Clone(i.envVars)
+	errs = make([]error, len(configs))
+	for idx, configFile := range configs {
+		// An empty name would make InitConfig fall back to GEOIP_CONFIG
+		if strings.TrimSpace(configFile) == "" {
+			errs[idx] = errors.New("config file name must not be empty")
+			continue
+		}
+
+		*i = instance{
+			input:   make([]InputConverter, 0),
+			output:  make([]OutputConverter, 0),
+			envVars: maps.Clone(envVars),
+		}
+
+		err := i.InitConfig(configFile)
+		if err == nil {
+			err = i.Run()
+		}
+		if err != nil {
+			errs[idx] = fmt.Errorf("config %s: %w", configFile, err)
+		}
+	}
+
+	return errs, nil
+}
+
+// RunWithCheckpoint works like Run, but skips the input phase and loads the
+// container from checkpointPath when the checkpoint is newer than the config
+// file and the data of all input converters. Otherwise, or if any input
+// converter does not implement ModTimer, the checkpoint is rewritten after
+// the input phase.
+func (i *instance) RunWithCheckpoint(checkpointPath string) error {
 	if len(i.input) == 0 || len(i.output) == 0 {
 		return errors.New("input type and output type must be specified")
 	}
 
 	container := NewContainer()
 
-	if err := i.RunInput(container); err != nil {
-		return err
+	if i.isCheckpointFresh(checkpointPath) {
+		if err := container.LoadCheckpoint(checkpointPath); err != nil {
+			return err
+		}
+		log.Printf("✅ [checkpoint] %s is up to date, skip input", checkpointPath)
+	} else {
+		if err := i.RunInput(container); err != nil {
+			return err
+		}
+		if err := i.renameEntries(container); err != nil {
+			return err
+		}
+		if err := container.WriteCheckpoint(checkpointPath); err != nil {
+			return err
+		}
+		log.Printf("✅ [checkpoint] input --> %s", checkpointPath)
 	}
 
-	if err := i.RunOutput(container); err != nil {
+	if err := i.RunOutputs(container); err != nil {
 		return err
 	}
 
+	logStats(container)
+
 	return nil
 }
+
+func (i *instance) isCheckpointFresh(checkpointPath string) bool {
+	info, err := os.Stat(checkpointPath)
+	if err != nil {
+		return false
+	}
+	checkpointTime := info.ModTime()
+
+	if i.configFile != "" {
+		configTime, err := GetModTime(i.configFile)
+		if err != nil || configTime.After(checkpointTime) {
+			return false
+		}
+	}
+
+	for _, ic := range i.input {
+		// Without a modification time the data of the input may have
+		// changed at any time, so the checkpoint cannot be trusted
+		mt, ok := ic.(ModTimer)
+		if !ok {
+			return false
+		}
+		modTime, err := mt.GetModTime()
+		if err != nil || modTime.After(checkpointTime) {
+			return false
+		}
+	}
+
+	return true
+}
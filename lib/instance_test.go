@@ -0,0 +1,91 @@
+package lib
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// recordedOutputs holds the names of the orderRecorder outputs in the order
+// they ran.
+var recordedOutputs []string
+
+type orderRecorder struct {
+	Type string
+	Name string
+}
+
+func (r *orderRecorder) GetType() string        { return r.Type }
+func (r *orderRecorder) GetAction() Action      { return ActionOutput }
+func (r *orderRecorder) GetDescription() string { return "records the order outputs run in" }
+
+func (r *orderRecorder) Output(Container) error {
+	recordedOutputs = append(recordedOutputs, r.Name)
+	return nil
+}
+
+func init() {
+	for _, typ := range []string{"orderRecorderA", "orderRecorderB", "orderRecorderC"} {
+		RegisterOutputConfigCreator(typ, func(action Action, data json.RawMessage) (OutputConverter, error) {
+			var tmp struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(data, &tmp); err != nil {
+				return nil, err
+			}
+			return &orderRecorder{Type: typ, Name: tmp.Name}, nil
+		})
+	}
+}
+
+func TestOutputOrder(t *testing.T) {
+	const outputs = `[
+		{"type": "orderRecorderC", "action": "output", "args": {"name": "c1"}},
+		{"type": "orderRecorderA", "action": "output", "args": {"name": "a1"}},
+		{"type": "orderRecorderB", "action": "output", "args": {"name": "b1"}},
+		{"type": "orderRecorderA", "action": "output", "args": {"name": "a2"}}
+	]`
+
+	tests := []struct {
+		name        string
+		outputOrder string
+		want        []string
+	}{
+		{
+			name:        "config order",
+			outputOrder: `[]`,
+			want:        []string{"c1", "a1", "b1", "a2"},
+		},
+		{
+			name:        "outputOrder",
+			outputOrder: `["orderRecorderB", "orderRecorderA", "orderRecorderC"]`,
+			want:        []string{"b1", "a1", "a2", "c1"},
+		},
+		{
+			name:        "partial outputOrder",
+			outputOrder: `["orderRecorderA"]`,
+			want:        []string{"a1", "a2", "c1", "b1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := NewInstance()
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := `{"output": ` + outputs + `, "outputOrder": ` + tt.outputOrder + `}`
+			if err := instance.InitConfigFromBytes([]byte(config)); err != nil {
+				t.Fatalf("InitConfigFromBytes: %v", err)
+			}
+
+			recordedOutputs = nil
+			if err := instance.RunOutput(NewContainer()); err != nil {
+				t.Fatalf("RunOutput: %v", err)
+			}
+			if !slices.Equal(recordedOutputs, tt.want) {
+				t.Errorf("outputs ran in order %v, want %v", recordedOutputs, tt.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,64 @@
 package lib
 
+import (
+	"log"
+	"time"
+)
+
 const (
 	ActionAdd    Action = "add"
 	ActionRemove Action = "remove"
 	ActionOutput Action = "output"
+	ActionClear  Action = "clear"
 
-	IPv4 IPType = "ipv4"
-	IPv6 IPType = "ipv6"
+	IPv4   IPType = "ipv4"
+	IPv6   IPType = "ipv6"
+	IPBoth IPType = "both"
 
 	CaseRemovePrefix CaseRemove = 0
 	CaseRemoveEntry  CaseRemove = 1
+	CaseClearEntry   CaseRemove = 2
 )
 
+// Debug enables debug messages, such as the ones logged when IPv4-mapped
+// IPv6 prefixes are unwrapped. It is off by default and is meant to be set
+// once at startup.
+var Debug bool
+
+// debugf logs a message like log.Printf, but only when Debug is enabled.
+func debugf(format string, v ...any) {
+	if Debug {
+		log.Printf(format, v...)
+	}
+}
+
 var ActionsRegistry = map[Action]bool{
 	ActionAdd:    true,
 	ActionRemove: true,
 	ActionOutput: true,
+	ActionClear:  true,
+}
+
+// ValidAction reports whether a is a known action.
+func ValidAction(a Action) bool {
+	return ActionsRegistry[a]
+}
+
+// ValidInputAction reports whether a is an action an input converter can
+// perform.
+func ValidInputAction(a Action) bool {
+	return a == ActionAdd || a == ActionRemove || a == ActionClear
+}
+
+// ValidOutputAction reports whether a is an action an output converter can
+// perform.
+func ValidOutputAction(a Action) bool {
+	return a == ActionOutput
+}
+
+// AllActions returns all known actions.
+func AllActions() []Action {
+	return []Action{ActionAdd, ActionRemove, ActionOutput, ActionClear}
 }
 
 type Action string
@@ -36,6 +79,12 @@ type Descriptioner interface {
 	GetDescription() string
 }
 
+// ModTimer is implemented by input converters which know when their
+// source data was last modified.
+type ModTimer interface {
+	GetModTime() (time.Time, error)
+}
+
 type InputConverter interface {
 	Typer
 	Actioner
@@ -0,0 +1,104 @@
+package lib
+
+import (
+	"net/netip"
+	"slices"
+
+	"go4.org/netipx"
+)
+
+// lookupRange maps a range of addresses to the entry containing them.
+type lookupRange struct {
+	ipRange netipx.IPRange
+	entry   *Entry
+}
+
+// Contains reports whether ip is covered by the entry.
+func (e *Entry) Contains(ip netip.Addr) bool {
+	if err := e.buildIPSet(); err != nil {
+		return false
+	}
+
+	ip = ip.Unmap()
+	switch {
+	case ip.Is4():
+		return e.hasIPv4Set() && e.ipv4Set.Contains(ip)
+	case ip.Is6():
+		return e.hasIPv6Set() && e.ipv6Set.Contains(ip)
+	default:
+		return false
+	}
+}
+
+// LookupIP returns the first entry, in sorted name order, which contains ip.
+// It uses the index built by BuildLookupIndex if there is one.
+func (c *container) LookupIP(ip netip.Addr) (*Entry, bool) {
+	if c.lookupIndex != nil {
+		return lookupIndexed(c.lookupIndex, ip.Unmap())
+	}
+
+	for _, name := range c.names() {
+		if entry := c.entries[name]; entry.Contains(ip) {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// BuildLookupIndex builds a sorted index of all addresses of the container,
+// so that LookupIP runs in O(log n). The index is dropped whenever entries
+// are added or removed through the container; call BuildLookupIndex again
+// after modifying entries directly.
+func (c *container) BuildLookupIndex() error {
+	var seen netipx.IPSetBuilder
+	index := make([]lookupRange, 0, 1024)
+
+	for _, name := range c.names() {
+		entry := c.entries[name]
+		set, err := entry.ipSet()
+		if err != nil {
+			return err
+		}
+
+		// Addresses already covered by an earlier entry keep pointing
+		// to that entry, so that the ranges in the index never overlap
+		var builder netipx.IPSetBuilder
+		builder.AddSet(set)
+		covered, err := seen.IPSet()
+		if err != nil {
+			return err
+		}
+		builder.RemoveSet(covered)
+		remaining, err := builder.IPSet()
+		if err != nil {
+			return err
+		}
+
+		for _, r := range remaining.Ranges() {
+			index = append(index, lookupRange{ipRange: r, entry: entry})
+		}
+		seen.AddSet(set)
+	}
+
+	slices.SortFunc(index, func(a, b lookupRange) int {
+		return a.ipRange.From().Compare(b.ipRange.From())
+	})
+	c.lookupIndex = index
+
+	return nil
+}
+
+// lookupIndexed returns the entry of the range of index which contains ip.
+func lookupIndexed(index []lookupRange, ip netip.Addr) (*Entry, bool) {
+	// Find the last range starting at or before ip
+	i, found := slices.BinarySearchFunc(index, ip, func(r lookupRange, ip netip.Addr) int {
+		return r.ipRange.From().Compare(ip)
+	})
+	if !found {
+		i--
+	}
+	if i < 0 || !index[i].ipRange.Contains(ip) {
+		return nil, false
+	}
+	return index[i].entry, true
+}
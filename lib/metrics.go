@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const defaultPushJobName = "geoip"
+
+// pushgateway holds the options for pushing the metrics of a run
+// to a Prometheus Pushgateway.
+type pushgateway struct {
+	URL           string
+	JobName       string
+	InstanceLabel string
+}
+
+// pushMetrics pushes the metrics of a run to the Pushgateway, replacing the
+// metrics previously pushed with the same job and instance labels.
+// container may be nil if the run failed before the input phase finished.
+func (p *pushgateway) pushMetrics(container Container, duration time.Duration, success bool) error {
+	registry := prometheus.NewRegistry()
+	setGauge := func(name, help string, value float64) {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+		gauge.Set(value)
+		registry.MustRegister(gauge)
+	}
+
+	successValue := 0.0
+	if success {
+		successValue = 1
+	}
+	setGauge("geoip_last_run_success", "Whether the last run succeeded.", successValue)
+	setGauge("geoip_last_run_duration_seconds", "Duration of the last run in seconds.", duration.Seconds())
+	setGauge("geoip_last_run_timestamp_seconds", "Unix time of the end of the last run.", float64(time.Now().Unix()))
+
+	if container != nil {
+		setGauge("geoip_entries", "Number of entries in the container.", float64(container.Len()))
+		if ipv4Count, ipv6Count, err := container.TotalPrefixCount(); err == nil {
+			setGauge("geoip_ipv4_prefixes", "Number of IPv4 prefixes in all entries.", float64(ipv4Count))
+			setGauge("geoip_ipv6_prefixes", "Number of IPv6 prefixes in all entries.", float64(ipv6Count))
+		}
+	}
+
+	jobName := p.JobName
+	if jobName == "" {
+		jobName = defaultPushJobName
+	}
+	pusher := push.New(p.URL, jobName).Gatherer(registry)
+	if p.InstanceLabel != "" {
+		pusher = pusher.Grouping("instance", p.InstanceLabel)
+	}
+
+	if err := pusher.Push(); err != nil {
+		return fmt.Errorf("failed to push metrics -> %s: %w", p.URL, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ImportFromMMDB adds all networks of the MMDB file at path to the container.
+// Each network goes into the entry named by the string found at the nested
+// field path countryCodeField of its record, e.g. ["country", "iso_code"].
+// Networks whose record has no such field are skipped.
+func (c *container) ImportFromMMDB(path string, countryCodeField []string) error {
+	if len(countryCodeField) == 0 {
+		return errors.New("country code field must be specified")
+	}
+
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	entries := make(map[string]*Entry)
+	networks := db.Networks(maxminddb.SkipAliasedNetworks)
+	for networks.Next() {
+		var record map[string]any
+		subnet, err := networks.Network(&record)
+		if err != nil {
+			return err
+		}
+
+		name := strings.ToUpper(strings.TrimSpace(lookupField(record, countryCodeField)))
+		if name == "" {
+			continue
+		}
+
+		entry, found := entries[name]
+		if !found {
+			entry = NewEntry(name)
+			entries[name] = entry
+		}
+
+		if err := entry.AddPrefix(subnet); err != nil {
+			return err
+		}
+	}
+
+	if networks.Err() != nil {
+		return networks.Err()
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if err := c.Add(entries[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupField returns the string at the nested field path of record,
+// or an empty string if there is none.
+func lookupField(record map[string]any, path []string) string {
+	var value any = record
+	for _, field := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return ""
+		}
+		value = m[field]
+	}
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,427 @@
+package lib
+
+import (
+	"io"
+	"iter"
+	"math/big"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// NewNamespacedContainer creates an empty container and returns the view of
+// its namespace, see WithNamespace.
+func NewNamespacedContainer(namespace string) Container {
+	return NewContainer().WithNamespace(namespace)
+}
+
+// WithNamespace returns a view of the entries of c in the namespace. The view
+// has no entries of its own: the entry "CN" of the view is stored in c as
+// "MYTENANT/CN", so it is also seen by the Loop, Len and outputs of c. The
+// view adds the namespace+"/" prefix to the names passed in and strips it
+// from the names returned. Nested namespaces can be given as "A/B".
+//
+// ForEachEntry, ForEachEntryParallel and the methods which modify the
+// container work on the entries of c in place. GetEntry, Loop, LookupIP and
+// the other methods which return entries or containers return copies named
+// without the namespace, so changes to them are not written back.
+func (c *container) WithNamespace(namespace string) Container {
+	return &namespacedContainer{
+		root:      c,
+		namespace: strings.Trim(strings.ToUpper(strings.TrimSpace(namespace)), "/"),
+	}
+}
+
+type namespacedContainer struct {
+	root        *container
+	namespace   string
+	lookupIndex []lookupRange
+}
+
+func (n *namespacedContainer) prefix() string {
+	return n.namespace + "/"
+}
+
+// qualify returns a copy of entry with the namespace prefix added to its name.
+func (n *namespacedContainer) qualify(entry *Entry) *Entry {
+	qualified := entry.clone()
+	qualified.name = n.prefix() + entry.GetName()
+	return qualified
+}
+
+// strip returns a copy of entry with the namespace prefix removed from its name.
+func (n *namespacedContainer) strip(entry *Entry) *Entry {
+	stripped := entry.clone()
+	stripped.name = strings.TrimPrefix(entry.GetName(), n.prefix())
+	return stripped
+}
+
+// local returns a container with copies of the entries in the namespace,
+// named without the namespace prefix.
+func (n *namespacedContainer) local() *container {
+	local := &container{
+		entries: make(map[string]*Entry),
+	}
+	for key, entry := range n.root.entries {
+		if name, found := strings.CutPrefix(key, n.prefix()); found {
+			local.entries[name] = n.strip(entry)
+		}
+	}
+	return local
+}
+
+// view calls fn with a container holding the entries of the namespace
+// themselves, not copies. The entries are named without the namespace
+// prefix while fn runs, and get their full names back afterwards.
+func (n *namespacedContainer) view(fn func(*container) error) error {
+	local := &container{
+		entries: make(map[string]*Entry),
+	}
+	for key, entry := range n.root.entries {
+		if name, found := strings.CutPrefix(key, n.prefix()); found {
+			entry.name = name
+			local.entries[name] = entry
+		}
+	}
+	defer func() {
+		for _, entry := range local.entries {
+			entry.name = n.prefix() + entry.name
+		}
+	}()
+
+	return fn(local)
+}
+
+// update works like view, but also writes the entries left in the container
+// back to c afterwards, as fn may add, remove or rename entries.
+func (n *namespacedContainer) update(fn func(*container) error) error {
+	n.root.lookupIndex = nil
+	n.lookupIndex = nil
+
+	return n.view(func(local *container) error {
+		defer func() {
+			for key := range n.root.entries {
+				if strings.HasPrefix(key, n.prefix()) {
+					delete(n.root.entries, key)
+				}
+			}
+			for name, entry := range local.entries {
+				n.root.entries[n.prefix()+name] = entry
+			}
+		}()
+
+		return fn(local)
+	})
+}
+
+func (n *namespacedContainer) WithNamespace(namespace string) Container {
+	return n.root.WithNamespace(n.prefix() + namespace)
+}
+
+func (n *namespacedContainer) GetEntry(name string) (*Entry, bool) {
+	entry, found := n.root.GetEntry(n.prefix() + strings.TrimSpace(name))
+	if !found {
+		return nil, false
+	}
+	return n.strip(entry), true
+}
+
+func (n *namespacedContainer) Add(entry *Entry, opts ...IgnoreIPOption) error {
+	n.lookupIndex = nil
+	return n.root.Add(n.qualify(entry), opts...)
+}
+
+func (n *namespacedContainer) Remove(entry *Entry, rCase CaseRemove, opts ...IgnoreIPOption) error {
+	n.lookupIndex = nil
+	return n.root.Remove(n.qualify(entry), rCase, opts...)
+}
+
+func (n *namespacedContainer) Len() int {
+	count := 0
+	for key := range n.root.entries {
+		if strings.HasPrefix(key, n.prefix()) {
+			count++
+		}
+	}
+	return count
+}
+
+func (n *namespacedContainer) Loop() <-chan *Entry {
+	return n.local().Loop()
+}
+
+func (n *namespacedContainer) OverlapsWith(name string, other Container, otherName string) (bool, []netip.Prefix, error) {
+	return n.local().OverlapsWith(name, other, otherName)
+}
+
+func (n *namespacedContainer) Diff(other Container) (DiffResult, error) {
+	return n.local().Diff(other)
+}
+
+func (n *namespacedContainer) GetLastModified() time.Time {
+	var lastModified time.Time
+	n.view(func(c *container) error {
+		lastModified = c.GetLastModified()
+		return nil
+	})
+	return lastModified
+}
+
+func (n *namespacedContainer) GroupByRIR() (map[string]Container, error) {
+	return n.local().GroupByRIR()
+}
+
+func (n *namespacedContainer) GroupByPrefix(prefixLen int) (map[string]Container, error) {
+	return n.local().GroupByPrefix(prefixLen)
+}
+
+func (n *namespacedContainer) KeyByASN() (map[uint32][]netip.Prefix, error) {
+	var result map[uint32][]netip.Prefix
+	err := n.view(func(c *container) error {
+		var err error
+		result, err = c.KeyByASN()
+		return err
+	})
+	return result, err
+}
+
+func (n *namespacedContainer) WriteCheckpoint(path string) error {
+	return n.view(func(c *container) error {
+		return c.WriteCheckpoint(path)
+	})
+}
+
+func (n *namespacedContainer) LoadCheckpoint(path string) error {
+	return n.update(func(c *container) error {
+		return c.LoadCheckpoint(path)
+	})
+}
+
+func (n *namespacedContainer) ForEachEntry(fn func(*Entry) error) error {
+	return n.update(func(c *container) error {
+		return c.ForEachEntry(fn)
+	})
+}
+
+func (n *namespacedContainer) ForEachEntryParallel(concurrency int, fn func(*Entry) error) error {
+	return n.update(func(c *container) error {
+		return c.ForEachEntryParallel(concurrency, fn)
+	})
+}
+
+func (n *namespacedContainer) Serialize(w io.Writer, codecName string) error {
+	return n.view(func(c *container) error {
+		return c.Serialize(w, codecName)
+	})
+}
+
+func (n *namespacedContainer) WriteTo(w io.Writer, format string) error {
+	return n.view(func(c *container) error {
+		return c.WriteTo(w, format)
+	})
+}
+
+func (n *namespacedContainer) Deserialize(r io.Reader, codecName string) error {
+	return n.update(func(c *container) error {
+		return c.Deserialize(r, codecName)
+	})
+}
+
+func (n *namespacedContainer) RenameEntry(oldName, newName string, force bool) error {
+	return n.update(func(c *container) error {
+		return c.RenameEntry(oldName, newName, force)
+	})
+}
+
+func (n *namespacedContainer) SwapEntries(nameA, nameB string) error {
+	return n.update(func(c *container) error {
+		return c.SwapEntries(nameA, nameB)
+	})
+}
+
+func (n *namespacedContainer) Compact() ([]CompactionReport, error) {
+	var reports []CompactionReport
+	err := n.update(func(c *container) error {
+		var err error
+		reports, err = c.Compact()
+		return err
+	})
+	return reports, err
+}
+
+func (n *namespacedContainer) ApplyWantedList(want []string) (Container, error) {
+	return n.local().ApplyWantedList(want)
+}
+
+func (n *namespacedContainer) ApplyExcludedList(exclude []string) (Container, error) {
+	return n.local().ApplyExcludedList(exclude)
+}
+
+func (n *namespacedContainer) ClearAll() error {
+	n.PruneByPredicate(func(string) bool {
+		return false
+	})
+	return nil
+}
+
+func (n *namespacedContainer) Reset() error {
+	return n.ClearAll()
+}
+
+func (n *namespacedContainer) Prune(retainList []string) error {
+	return n.update(func(c *container) error {
+		return c.Prune(retainList)
+	})
+}
+
+func (n *namespacedContainer) PruneByPredicate(keep func(name string) bool) int {
+	removed := 0
+	for key := range n.root.entries {
+		if name, found := strings.CutPrefix(key, n.prefix()); found && !keep(name) {
+			delete(n.root.entries, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		n.root.lookupIndex = nil
+		n.lookupIndex = nil
+	}
+	return removed
+}
+
+func (n *namespacedContainer) ApplyTransformers(names []string, args map[string]any) error {
+	return n.update(func(c *container) error {
+		return c.ApplyTransformers(names, args)
+	})
+}
+
+func (n *namespacedContainer) TopNByPrefixCount(num int) ([]string, error) {
+	var names []string
+	err := n.view(func(c *container) error {
+		var err error
+		names, err = c.TopNByPrefixCount(num)
+		return err
+	})
+	return names, err
+}
+
+func (n *namespacedContainer) TotalPrefixCount() (ipv4Count int, ipv6Count int, err error) {
+	err = n.view(func(c *container) error {
+		var err error
+		ipv4Count, ipv6Count, err = c.TotalPrefixCount()
+		return err
+	})
+	return ipv4Count, ipv6Count, err
+}
+
+func (n *namespacedContainer) TotalIPCount() (*big.Int, error) {
+	var count *big.Int
+	err := n.view(func(c *container) error {
+		var err error
+		count, err = c.TotalIPCount()
+		return err
+	})
+	return count, err
+}
+
+func (n *namespacedContainer) FilterByMinIPCount(minIPs *big.Int) (Container, error) {
+	return n.local().FilterByMinIPCount(minIPs)
+}
+
+func (n *namespacedContainer) Snapshot() (Container, error) {
+	return n.local().Snapshot()
+}
+
+func (n *namespacedContainer) RestoreSnapshot(snap Container) error {
+	return n.update(func(c *container) error {
+		return c.RestoreSnapshot(snap)
+	})
+}
+
+func (n *namespacedContainer) EstimatedMemoryBytes() int64 {
+	var size int64
+	n.view(func(c *container) error {
+		size = c.EstimatedMemoryBytes()
+		return nil
+	})
+	return size
+}
+
+func (n *namespacedContainer) ImportFromMMDB(path string, countryCodeField []string) error {
+	return n.update(func(c *container) error {
+		return c.ImportFromMMDB(path, countryCodeField)
+	})
+}
+
+func (n *namespacedContainer) ImportFromCSV(r io.Reader, networkCol, countryCol int, hasHeader bool, opts ...CSVOption) error {
+	return n.update(func(c *container) error {
+		return c.ImportFromCSV(r, networkCol, countryCol, hasHeader, opts...)
+	})
+}
+
+// LookupIP returns a copy of the first entry of the namespace, in sorted
+// name order, which contains ip. It uses the index built by BuildLookupIndex
+// of the view if there is one.
+func (n *namespacedContainer) LookupIP(ip netip.Addr) (*Entry, bool) {
+	if n.lookupIndex != nil {
+		entry, found := lookupIndexed(n.lookupIndex, ip.Unmap())
+		if !found {
+			return nil, false
+		}
+		return n.strip(entry), true
+	}
+
+	var result *Entry
+	n.view(func(c *container) error {
+		if entry, found := c.LookupIP(ip); found {
+			result = entry.clone()
+		}
+		return nil
+	})
+	return result, result != nil
+}
+
+// BuildLookupIndex builds the index of the entries of the namespace used by
+// LookupIP of the view. The index is dropped whenever entries are added or
+// removed through the view.
+func (n *namespacedContainer) BuildLookupIndex() error {
+	return n.view(func(c *container) error {
+		if err := c.BuildLookupIndex(); err != nil {
+			return err
+		}
+		n.lookupIndex = c.lookupIndex
+		return nil
+	})
+}
+
+func (n *namespacedContainer) AssertEntryExists(name string) error {
+	return n.view(func(c *container) error {
+		return c.AssertEntryExists(name)
+	})
+}
+
+func (n *namespacedContainer) AssertEntryHasAtLeast(name string, minPrefixes int) error {
+	return n.view(func(c *container) error {
+		return c.AssertEntryHasAtLeast(name, minPrefixes)
+	})
+}
+
+func (n *namespacedContainer) AssertConsistency(opts ...ConsistencyOption) error {
+	return n.view(func(c *container) error {
+		return c.AssertConsistency(opts...)
+	})
+}
+
+func (n *namespacedContainer) OrderedLoop(less func(a, b *Entry) bool) (iter.Seq[*Entry], error) {
+	return n.local().OrderedLoop(less)
+}
+
+func (n *namespacedContainer) ForEachPrefix(fn func(country string, prefix netip.Prefix) error) error {
+	return n.view(func(c *container) error {
+		return c.ForEachPrefix(fn)
+	})
+}
+
+func (n *namespacedContainer) IterPrefixes() iter.Seq2[string, netip.Prefix] {
+	return n.local().IterPrefixes()
+}
@@ -0,0 +1,139 @@
+package lib
+
+import (
+	"bytes"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestNamespaceSharesEntries(t *testing.T) {
+	root := NewContainer()
+	tenant := root.WithNamespace("mytenant")
+
+	entry := NewEntry("CN")
+	if err := entry.AddPrefix("1.0.1.0/24"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tenant.Add(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.GetName() != "CN" {
+		t.Errorf("Add renamed the entry passed in to %s", entry.GetName())
+	}
+
+	// The root sees the entry with the namespace prefix
+	if root.Len() != 1 || tenant.Len() != 1 {
+		t.Fatalf("Len = %d in root and %d in namespace, want 1 and 1", root.Len(), tenant.Len())
+	}
+	var names []string
+	for entry := range root.Loop() {
+		names = append(names, entry.GetName())
+	}
+	if !slices.Equal(names, []string{"MYTENANT/CN"}) {
+		t.Errorf("root Loop = %v, want [MYTENANT/CN]", names)
+	}
+
+	got, found := tenant.GetEntry("cn")
+	if !found || got.GetName() != "CN" {
+		t.Fatalf("GetEntry(cn) = %v, %v, want entry CN", got, found)
+	}
+
+	// ForEachEntry changes the entries of the root in place
+	err := tenant.ForEachEntry(func(entry *Entry) error {
+		if entry.GetName() != "CN" {
+			t.Errorf("ForEachEntry got entry %s, want CN", entry.GetName())
+		}
+		return entry.AddPrefix("1.0.2.0/24")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEntry, found := root.GetEntry("MYTENANT/CN")
+	if !found {
+		t.Fatal("entry MYTENANT/CN not found in root")
+	}
+	prefixes, err := rootEntry.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1.0.1.0/24", "1.0.2.0/24"}; !slices.Equal(prefixes, want) {
+		t.Errorf("root entry has %v, want %v", prefixes, want)
+	}
+	if rootEntry.GetName() != "MYTENANT/CN" {
+		t.Errorf("root entry is named %s after ForEachEntry, want MYTENANT/CN", rootEntry.GetName())
+	}
+
+	// Nested namespaces are stored in the same root
+	if err := tenant.WithNamespace("eu").Add(NewEntry("DE")); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := root.GetEntry("MYTENANT/EU/DE"); !found {
+		t.Error("entry MYTENANT/EU/DE not found in root")
+	}
+
+	if err := tenant.RenameEntry("CN", "HK", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := root.GetEntry("MYTENANT/HK"); !found {
+		t.Error("entry MYTENANT/HK not found in root after RenameEntry")
+	}
+	if _, found := root.GetEntry("MYTENANT/CN"); found {
+		t.Error("entry MYTENANT/CN still in root after RenameEntry")
+	}
+
+	if err := tenant.BuildLookupIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if got, found := tenant.LookupIP(netip.MustParseAddr("1.0.2.1")); !found || got.GetName() != "HK" {
+		t.Errorf("LookupIP(1.0.2.1) = %v, %v, want entry HK", got, found)
+	}
+
+	tenant.ClearAll()
+	if root.Len() != 0 {
+		t.Errorf("root Len = %d after ClearAll of the namespace, want 0", root.Len())
+	}
+}
+
+func TestNamespaceSerializeRoundTrip(t *testing.T) {
+	for _, codecName := range []string{"gob", "json", "msgpack"} {
+		t.Run(codecName, func(t *testing.T) {
+			src := NewContainer()
+			entry := NewEntry("CN")
+			if err := entry.AddPrefix("1.0.1.0/24"); err != nil {
+				t.Fatal(err)
+			}
+			if err := src.WithNamespace("MYTENANT").Add(entry); err != nil {
+				t.Fatal(err)
+			}
+
+			// Through the root, the namespace is kept in the entry names
+			var buf bytes.Buffer
+			if err := src.Serialize(&buf, codecName); err != nil {
+				t.Fatalf("Serialize: %v", err)
+			}
+			dst := NewContainer()
+			if err := dst.Deserialize(&buf, codecName); err != nil {
+				t.Fatalf("Deserialize: %v", err)
+			}
+			if _, found := dst.WithNamespace("MYTENANT").GetEntry("CN"); !found {
+				t.Error("entry CN not found in namespace MYTENANT after Deserialize")
+			}
+
+			// Through a view, the entries move to the namespace they are read into
+			buf.Reset()
+			if err := src.WithNamespace("MYTENANT").Serialize(&buf, codecName); err != nil {
+				t.Fatalf("Serialize: %v", err)
+			}
+			if err := dst.WithNamespace("OTHER").Deserialize(&buf, codecName); err != nil {
+				t.Fatalf("Deserialize: %v", err)
+			}
+			if _, found := dst.GetEntry("OTHER/CN"); !found {
+				t.Error("entry OTHER/CN not found after Deserialize into namespace OTHER")
+			}
+			if dst.Len() != 2 {
+				t.Errorf("Len = %d, want 2", dst.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"iter"
+	"slices"
+)
+
+// OrderedLoop returns an iterator over the entries sorted by less.
+// Entries which are equal by less are kept in name order.
+func (c *container) OrderedLoop(less func(a, b *Entry) bool) (iter.Seq[*Entry], error) {
+	entries := make([]*Entry, 0, c.Len())
+	err := c.ForEachEntry(func(entry *Entry) error {
+		// Build the IP sets up front, so that comparators can use them
+		if err := entry.buildIPSet(); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(entries, func(a, b *Entry) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return slices.Values(entries), nil
+}
+
+// ByNameAsc orders entries by name in ascending order.
+func ByNameAsc() func(a, b *Entry) bool {
+	return func(a, b *Entry) bool {
+		return a.GetName() < b.GetName()
+	}
+}
+
+// ByNameDesc orders entries by name in descending order.
+func ByNameDesc() func(a, b *Entry) bool {
+	return func(a, b *Entry) bool {
+		return a.GetName() > b.GetName()
+	}
+}
+
+// ByIPCountDesc orders entries by the number of IP addresses they cover,
+// in descending order.
+func ByIPCountDesc() func(a, b *Entry) bool {
+	return func(a, b *Entry) bool {
+		countA, errA := a.IPCount()
+		countB, errB := b.IPCount()
+		if errA != nil || errB != nil {
+			return false
+		}
+		return countA.Cmp(countB) > 0
+	}
+}
+
+// ByPrefixCountDesc orders entries by their number of prefixes,
+// IPv4 and IPv6 combined, in descending order.
+func ByPrefixCountDesc() func(a, b *Entry) bool {
+	return func(a, b *Entry) bool {
+		ipv4A, ipv6A, errA := a.prefixCount()
+		ipv4B, ipv6B, errB := b.prefixCount()
+		if errA != nil || errB != nil {
+			return false
+		}
+		return ipv4A+ipv6A > ipv4B+ipv6B
+	}
+}
@@ -0,0 +1,108 @@
+package lib
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"go4.org/netipx"
+)
+
+// ParseStrictPrefix parses s as a prefix and returns an error if any host
+// bits are set, e.g. for 192.168.1.5/24. IPv4-mapped IPv6 prefixes are
+// unwrapped to IPv4 prefixes.
+func ParseStrictPrefix(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if prefix != prefix.Masked() {
+		return netip.Prefix{}, fmt.Errorf("%w: %s has host bits set", ErrInvalidPrefix, s)
+	}
+	return unmapPrefix(prefix), nil
+}
+
+// ParseOrMaskPrefix parses s as a prefix and clears any host bits.
+// IPv4-mapped IPv6 prefixes are unwrapped to IPv4 prefixes.
+func ParseOrMaskPrefix(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return unmapPrefix(prefix.Masked()), nil
+}
+
+// unmapPrefix unwraps a prefix inside ::ffff:0:0/96, such as
+// ::ffff:1.2.3.0/120, to the IPv4 prefix 1.2.3.0/24.
+func unmapPrefix(prefix netip.Prefix) netip.Prefix {
+	if !prefix.Addr().Is4In6() || prefix.Bits() < 96 {
+		return prefix
+	}
+
+	unmapped := netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	debugf("🔍 unwrap IPv4-mapped IPv6 prefix %s to %s\n", prefix, unmapped)
+	return unmapped
+}
+
+func buildPrefixSet(prefixes []netip.Prefix) (*netipx.IPSet, error) {
+	var builder netipx.IPSetBuilder
+	for _, prefix := range prefixes {
+		builder.AddPrefix(prefix)
+	}
+	return builder.IPSet()
+}
+
+// PrefixSetsEqual reports whether a and b cover exactly the same IP addresses,
+// regardless of how the prefixes are split or ordered.
+func PrefixSetsEqual(a, b []netip.Prefix) bool {
+	setA, err := buildPrefixSet(a)
+	if err != nil {
+		return false
+	}
+	setB, err := buildPrefixSet(b)
+	if err != nil {
+		return false
+	}
+	return setA.Equal(setB)
+}
+
+// PrefixSetIsSubset reports whether every IP address covered by sub
+// is also covered by super.
+func PrefixSetIsSubset(sub, super []netip.Prefix) bool {
+	setSub, err := buildPrefixSet(sub)
+	if err != nil {
+		return false
+	}
+	setSuper, err := buildPrefixSet(super)
+	if err != nil {
+		return false
+	}
+
+	var builder netipx.IPSetBuilder
+	builder.AddSet(setSub)
+	builder.RemoveSet(setSuper)
+	diff, err := builder.IPSet()
+	if err != nil {
+		return false
+	}
+	return len(diff.Ranges()) == 0
+}
+
+// comparePrefix orders prefixes by address, then by prefix length.
+func comparePrefix(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// countIPs returns the number of IP addresses in set.
+func countIPs(set *netipx.IPSet) *big.Int {
+	count := new(big.Int)
+	for _, r := range set.Ranges() {
+		from, to := r.From().As16(), r.To().As16()
+		size := new(big.Int).Sub(new(big.Int).SetBytes(to[:]), new(big.Int).SetBytes(from[:]))
+		count.Add(count, size.Add(size, big.NewInt(1)))
+	}
+	return count
+}
@@ -0,0 +1,84 @@
+package lib
+
+import "strings"
+
+const (
+	RIRAFRINIC = "AFRINIC"
+	RIRAPNIC   = "APNIC"
+	RIRARIN    = "ARIN"
+	RIRLACNIC  = "LACNIC"
+	RIRRIPE    = "RIPE"
+)
+
+// rirCountries maps every Regional Internet Registry to the ISO 3166-1
+// alpha-2 codes of the countries and territories in its service region.
+var rirCountries = map[string][]string{
+	RIRAFRINIC: {
+		"AO", "BF", "BI", "BJ", "BW", "CD", "CF", "CG", "CI", "CM", "CV", "DJ", "DZ", "EG", "EH", "ER",
+		"ET", "GA", "GH", "GM", "GN", "GQ", "GW", "IO", "KE", "KM", "LR", "LS", "LY", "MA", "MG", "ML",
+		"MR", "MU", "MW", "MZ", "NA", "NE", "NG", "RE", "RW", "SC", "SD", "SL", "SN", "SO", "SS", "ST",
+		"SZ", "TD", "TF", "TG", "TN", "TZ", "UG", "YT", "ZA", "ZM", "ZW",
+	},
+	RIRAPNIC: {
+		"AF", "AS", "AU", "BD", "BN", "BT", "CC", "CK", "CN", "CX", "FJ", "FM", "GU", "HK", "ID", "IN",
+		"JP", "KH", "KI", "KP", "KR", "LA", "LK", "MH", "MM", "MN", "MO", "MP", "MV", "MY", "NC", "NF",
+		"NP", "NR", "NU", "NZ", "PF", "PG", "PH", "PK", "PN", "PW", "SB", "SG", "TH", "TK", "TL", "TO",
+		"TV", "TW", "VN", "VU", "WF", "WS",
+	},
+	RIRARIN: {
+		"AG", "AI", "AQ", "BB", "BL", "BM", "BS", "BV", "CA", "DM", "GD", "GP", "HM", "JM", "KN", "KY",
+		"LC", "MF", "MQ", "MS", "PM", "PR", "SH", "TC", "UM", "US", "VC", "VG", "VI",
+	},
+	RIRLACNIC: {
+		"AR", "AW", "BO", "BQ", "BR", "BZ", "CL", "CO", "CR", "CU", "CW", "DO", "EC", "FK", "GF", "GS",
+		"GT", "GY", "HN", "HT", "MX", "NI", "PA", "PE", "PY", "SR", "SV", "SX", "TT", "UY", "VE",
+	},
+	RIRRIPE: {
+		"AD", "AE", "AL", "AM", "AT", "AX", "AZ", "BA", "BE", "BG", "BH", "BY", "CH", "CY", "CZ", "DE",
+		"DK", "EE", "ES", "EU", "FI", "FO", "FR", "GB", "GE", "GG", "GI", "GL", "GR", "HR", "HU", "IE",
+		"IL", "IM", "IQ", "IR", "IS", "IT", "JE", "JO", "KG", "KW", "KZ", "LB", "LI", "LT", "LU", "LV",
+		"MC", "MD", "ME", "MK", "MT", "NL", "NO", "OM", "PL", "PS", "PT", "QA", "RO", "RS", "RU", "SA",
+		"SE", "SI", "SJ", "SK", "SM", "SY", "TJ", "TM", "TR", "UA", "UZ", "VA", "XK", "YE",
+	},
+}
+
+var countryRIR = func() map[string]string {
+	m := make(map[string]string)
+	for rir, countries := range rirCountries {
+		for _, country := range countries {
+			m[country] = rir
+		}
+	}
+	return m
+}()
+
+// GetRIR returns the Regional Internet Registry serving the country code,
+// or an empty string if the code is unknown.
+func GetRIR(countryCode string) string {
+	return countryRIR[strings.ToUpper(strings.TrimSpace(countryCode))]
+}
+
+// GroupByRIR partitions the entries of the container into one container per
+// Regional Internet Registry, keyed by the RIR name. Entries whose name is not
+// a known country code are left out. The entries in the returned containers
+// are copies, so changing them does not affect c.
+func (c *container) GroupByRIR() (map[string]Container, error) {
+	groups := make(map[string]Container, len(rirCountries))
+	for rir := range rirCountries {
+		groups[rir] = NewContainer()
+	}
+
+	for _, name := range c.names() {
+		rir := GetRIR(name)
+		if rir == "" {
+			continue
+		}
+
+		entry, _ := c.GetEntry(name)
+		if err := groups[rir].Add(entry.clone()); err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,54 @@
+package lib
+
+// SetUnion returns the distinct elements of a and b, in order of first appearance.
+func SetUnion[T comparable](a, b []T) []T {
+	seen := make(map[T]bool, len(a)+len(b))
+	result := make([]T, 0, len(a)+len(b))
+	for _, list := range [][]T{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// SetIntersection returns the distinct elements of a which are also in b,
+// in the order of a.
+func SetIntersection[T comparable](a, b []T) []T {
+	inB := make(map[T]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	seen := make(map[T]bool, len(a))
+	result := make([]T, 0)
+	for _, v := range a {
+		if inB[v] && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// SetDifference returns the distinct elements of a which are not in b,
+// in the order of a.
+func SetDifference[T comparable](a, b []T) []T {
+	inB := make(map[T]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	seen := make(map[T]bool, len(a))
+	result := make([]T, 0)
+	for _, v := range a {
+		if !inB[v] && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// GroupByPrefix splits the container into sub-containers by the covering
+// prefix of length prefixLen that each entry mainly resides in, e.g. by /8
+// blocks. The keys are the covering prefixes, such as "1.0.0.0/8". Every
+// entry is put whole into the sub-container of the block which covers the
+// most of its addresses, with ties going to the lowest block. Prefixes
+// shorter than prefixLen count as blocks of their own. Entries without
+// prefixes are left out.
+func (c *container) GroupByPrefix(prefixLen int) (map[string]Container, error) {
+	if prefixLen < 0 || prefixLen > 128 {
+		return nil, fmt.Errorf("invalid prefix length %d", prefixLen)
+	}
+
+	groups := make(map[string]Container)
+	for _, name := range c.names() {
+		entry := c.entries[name]
+		prefixes, err := entry.MarshalPrefix()
+		if err != nil {
+			return nil, err
+		}
+
+		// Count the addresses of the entry in each block
+		sizes := make(map[netip.Prefix]*big.Int)
+		for _, prefix := range prefixes {
+			block := prefix
+			if prefix.Bits() >= prefixLen {
+				block = netip.PrefixFrom(prefix.Addr(), prefixLen).Masked()
+			}
+			if sizes[block] == nil {
+				sizes[block] = new(big.Int)
+			}
+			sizes[block].Add(sizes[block], prefixSize(prefix))
+		}
+
+		var main netip.Prefix
+		for block, size := range sizes {
+			if !main.IsValid() {
+				main = block
+				continue
+			}
+			if cmp := size.Cmp(sizes[main]); cmp > 0 || cmp == 0 && comparePrefix(block, main) < 0 {
+				main = block
+			}
+		}
+		if !main.IsValid() {
+			continue
+		}
+
+		key := main.String()
+		group, found := groups[key]
+		if !found {
+			group = NewContainer()
+			groups[key] = group
+		}
+		if err := group.Add(entry.clone()); err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
+
+// prefixSize returns the number of IP addresses in prefix.
+func prefixSize(prefix netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+}
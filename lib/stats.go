@@ -0,0 +1,179 @@
+package lib
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+)
+
+// prefixCount returns the number of minimal IPv4 and IPv6 prefixes of the entry.
+func (e *Entry) prefixCount() (ipv4Count, ipv6Count int, err error) {
+	if err := e.buildIPSet(); err != nil {
+		return 0, 0, err
+	}
+	if e.hasIPv4Set() {
+		ipv4Count = len(e.ipv4Set.Prefixes())
+	}
+	if e.hasIPv6Set() {
+		ipv6Count = len(e.ipv6Set.Prefixes())
+	}
+	return ipv4Count, ipv6Count, nil
+}
+
+// Len returns the number of minimal prefixes of the entry, IPv4 and IPv6
+// combined, or 0 if the IP sets cannot be built.
+func (e *Entry) Len() int {
+	ipv4Count, ipv6Count, err := e.prefixCount()
+	if err != nil {
+		return 0
+	}
+	return ipv4Count + ipv6Count
+}
+
+// IPv4Len returns the number of minimal IPv4 prefixes of the entry,
+// or 0 if the IP sets cannot be built.
+func (e *Entry) IPv4Len() int {
+	ipv4Count, _, err := e.prefixCount()
+	if err != nil {
+		return 0
+	}
+	return ipv4Count
+}
+
+// IPv6Len returns the number of minimal IPv6 prefixes of the entry,
+// or 0 if the IP sets cannot be built.
+func (e *Entry) IPv6Len() int {
+	_, ipv6Count, err := e.prefixCount()
+	if err != nil {
+		return 0
+	}
+	return ipv6Count
+}
+
+// TopNByPrefixCount returns the names of up to n entries with the most
+// prefixes, IPv4 and IPv6 combined, in descending order. Entries with the
+// same count are ordered by name.
+func (c *container) TopNByPrefixCount(n int) ([]string, error) {
+	type entryCount struct {
+		name  string
+		count int
+	}
+
+	counts := make([]entryCount, 0, c.Len())
+	err := c.ForEachEntry(func(entry *Entry) error {
+		ipv4Count, ipv6Count, err := entry.prefixCount()
+		if err != nil {
+			return err
+		}
+		counts = append(counts, entryCount{name: entry.GetName(), count: ipv4Count + ipv6Count})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(counts, func(a, b entryCount) int {
+		return cmp.Compare(b.count, a.count)
+	})
+
+	n = max(0, min(n, len(counts)))
+	names := make([]string, 0, n)
+	for _, ec := range counts[:n] {
+		names = append(names, ec.name)
+	}
+
+	return names, nil
+}
+
+// IPCount returns the number of IP addresses covered by the entry.
+func (e *Entry) IPCount() (*big.Int, error) {
+	if err := e.buildIPSet(); err != nil {
+		return nil, err
+	}
+
+	count := new(big.Int)
+	if e.hasIPv4Set() {
+		count.Add(count, countIPs(e.ipv4Set))
+	}
+	if e.hasIPv6Set() {
+		count.Add(count, countIPs(e.ipv6Set))
+	}
+	return count, nil
+}
+
+// TotalPrefixCount returns the number of IPv4 and IPv6 prefixes of all entries.
+func (c *container) TotalPrefixCount() (ipv4Count int, ipv6Count int, err error) {
+	err = c.ForEachEntry(func(entry *Entry) error {
+		v4, v6, err := entry.prefixCount()
+		if err != nil {
+			return err
+		}
+		ipv4Count += v4
+		ipv6Count += v6
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return ipv4Count, ipv6Count, nil
+}
+
+// TotalIPCount returns the sum of the IP counts of all entries.
+func (c *container) TotalIPCount() (*big.Int, error) {
+	total := new(big.Int)
+	err := c.ForEachEntry(func(entry *Entry) error {
+		count, err := entry.IPCount()
+		if err != nil {
+			return err
+		}
+		total.Add(total, count)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// FilterByMinIPCount returns a new container holding copies of the entries
+// which cover at least minIPs IP addresses.
+func (c *container) FilterByMinIPCount(minIPs *big.Int) (Container, error) {
+	filtered := NewContainer()
+	err := c.ForEachEntry(func(entry *Entry) error {
+		count, err := entry.IPCount()
+		if err != nil {
+			return err
+		}
+		if minIPs != nil && count.Cmp(minIPs) < 0 {
+			return nil
+		}
+		return filtered.Add(entry.clone())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// Rough per-item sizes used by EstimatedMemoryBytes.
+const (
+	ipv4PrefixBytes = 16
+	ipv6PrefixBytes = 28
+	entryOverhead   = 256
+)
+
+// EstimatedMemoryBytes returns a rough estimate of the memory used by the
+// entries of the container, for capacity planning only. Entries whose IP
+// sets cannot be built are counted by their overhead alone.
+func (c *container) EstimatedMemoryBytes() int64 {
+	var total int64
+	for _, entry := range c.entries {
+		total += entryOverhead
+		ipv4Count, ipv6Count, err := entry.prefixCount()
+		if err != nil {
+			continue
+		}
+		total += int64(ipv4Count)*ipv4PrefixBytes + int64(ipv6Count)*ipv6PrefixBytes
+	}
+	return total
+}
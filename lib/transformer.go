@@ -0,0 +1,122 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+
+	"go4.org/netipx"
+)
+
+var transformerMap = make(map[string]Transformer)
+
+// Transformer modifies the entries of a container in place.
+type Transformer func(container Container, args map[string]any) error
+
+func init() {
+	RegisterTransformer("removeBogons", removeBogons)
+	RegisterTransformer("aggregate", aggregate)
+	RegisterTransformer("deduplicateCrossEntry", deduplicateCrossEntry)
+}
+
+func RegisterTransformer(name string, fn Transformer) error {
+	name = strings.TrimSpace(name)
+	if _, ok := transformerMap[name]; ok {
+		return ErrDuplicatedTransformer
+	}
+	transformerMap[name] = fn
+	return nil
+}
+
+// ApplyTransformers applies the named transformers in order to a copy of the
+// container, and only replaces the entries of the container if all of them
+// succeed. args is passed to every transformer.
+func (c *container) ApplyTransformers(names []string, args map[string]any) error {
+	transformers := make([]Transformer, 0, len(names))
+	for _, name := range names {
+		fn, found := transformerMap[strings.TrimSpace(name)]
+		if !found {
+			return fmt.Errorf("%w: %s", ErrUnknownTransformer, name)
+		}
+		transformers = append(transformers, fn)
+	}
+
+	working, err := MergeContainers(c)
+	if err != nil {
+		return err
+	}
+
+	for i, fn := range transformers {
+		if err := fn(working, args); err != nil {
+			return fmt.Errorf("transformer %s: %w", names[i], err)
+		}
+	}
+
+	c.entries = working.(*container).entries
+	c.lookupIndex = nil
+
+	return nil
+}
+
+// removeBogons removes the bogon ranges from all entries,
+// and drops the entries left empty.
+func removeBogons(container Container, _ map[string]any) error {
+	return removeSetFromEntries(container, func(*Entry) (*netipx.IPSet, error) {
+		return bogonSet(), nil
+	})
+}
+
+// aggregate merges the prefixes of every entry into the minimal set of prefixes.
+func aggregate(container Container, _ map[string]any) error {
+	return container.ForEachEntry(func(entry *Entry) error {
+		return entry.Compact()
+	})
+}
+
+// deduplicateCrossEntry keeps every IP address only in the first entry,
+// in sorted name order, that contains it, and drops the entries left empty.
+func deduplicateCrossEntry(container Container, _ map[string]any) error {
+	var seen netipx.IPSetBuilder
+	return removeSetFromEntries(container, func(entry *Entry) (*netipx.IPSet, error) {
+		set, err := seen.IPSet()
+		if err != nil {
+			return nil, err
+		}
+		entrySet, err := entry.ipSet()
+		if err != nil {
+			return nil, err
+		}
+		seen.AddSet(entrySet)
+		return set, nil
+	})
+}
+
+func removeSetFromEntries(container Container, setFor func(*Entry) (*netipx.IPSet, error)) error {
+	empty := make([]*Entry, 0)
+	err := container.ForEachEntry(func(entry *Entry) error {
+		set, err := setFor(entry)
+		if err != nil {
+			return err
+		}
+		entry.removeSet(set)
+
+		remaining, err := entry.ipSet()
+		if err != nil {
+			return err
+		}
+		if len(remaining.Ranges()) == 0 {
+			empty = append(empty, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range empty {
+		if err := container.Remove(entry, CaseRemoveEntry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteTo writes the container to w in the output format registered as
+// format, with the default options of the format. The output converter
+// writes its files to a temporary directory, which are then copied to w
+// in name order.
+func (c *container) WriteTo(w io.Writer, format string) error {
+	dir, err := os.MkdirTemp("", "geoip-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	args, err := json.Marshal(map[string]string{"outputDir": dir})
+	if err != nil {
+		return err
+	}
+	converter, err := createOutputConfig(format, ActionOutput, args)
+	if err != nil {
+		return fmt.Errorf("output format %s: %w", format, err)
+	}
+
+	if err := converter.Output(c); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
@@ -1,20 +1,129 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/v2fly/geoip/lib"
 )
 
 var (
 	list       = flag.Bool("l", false, "List all available input and output formats")
-	configFile = flag.String("c", "config.json", "Path to the config file")
+	debug      = flag.Bool("debug", false, "Log debug messages, e.g. when IPv4-mapped IPv6 prefixes are unwrapped")
+	envFile    = flag.String("env-file", "", "Path to a .env file with variables for ${VAR} in the config")
+	configFile = flag.String("c", "", "Path to the config file, or - to read it from stdin (default env GEOIP_INPUTS, then env GEOIP_CONFIG, then env GEOIP_CONFIG_FILE, then config.json)")
+	batchFile  = flag.String("batch-config", "", "Path to a file listing config files to run in turn, one per line")
+
+	inputFile  = flag.String("inputFile", "", "Path or URL of a file to convert directly, without a config file")
+	inputType  = flag.String("input", "", "Input format of -inputFile, detected from the file if omitted")
+	outputFile = flag.String("outputFile", "", "Path of the converted file")
+	outputType = flag.String("output", "", "Output format, detected from the extension of -outputFile if omitted")
 )
 
+// quickInputArgs returns the args of an input config of format which reads file.
+func quickInputArgs(format, file string) map[string]any {
+	switch format {
+	case "text":
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		return map[string]any{"name": name, "uri": file}
+	case "maxmindGeoLite2CountryCSV":
+		// The blocks files sit next to the country locations file
+		country := filepath.Join(filepath.Dir(file), "GeoLite2-Country-Locations-en.csv")
+		if strings.Contains(strings.ToLower(filepath.Base(file)), "ipv6") {
+			return map[string]any{"country": country, "ipv6": file}
+		}
+		return map[string]any{"country": country, "ipv4": file}
+	default:
+		return map[string]any{"uri": file}
+	}
+}
+
+// quickOutputArgs returns the args of an output config of format which writes file.
+func quickOutputArgs(format, file string) map[string]any {
+	switch {
+	case file == "":
+		return nil
+	case format == "text":
+		// The text format writes one file per list into the directory
+		return map[string]any{"outputDir": filepath.Dir(file), "outputExtension": filepath.Ext(file)}
+	default:
+		return map[string]any{"outputDir": filepath.Dir(file), "outputName": filepath.Base(file)}
+	}
+}
+
+// runQuickConversion converts -inputFile to -outputFile without a config
+// file. The formats are detected from the files when -input or -output is
+// omitted.
+func runQuickConversion(instance lib.Instance) error {
+	input := *inputType
+	if input == "" {
+		format, err := lib.DetectInputFormat(*inputFile)
+		if err != nil {
+			return err
+		}
+		input = format
+	}
+
+	output := *outputType
+	if output == "" {
+		if *outputFile == "" {
+			return errors.New("either -output or -outputFile must be specified with -inputFile")
+		}
+		format, err := lib.DetectOutputFormat(*outputFile)
+		if err != nil {
+			return err
+		}
+		output = format
+	}
+
+	type converterConfig struct {
+		Type   string         `json:"type"`
+		Action lib.Action     `json:"action"`
+		Args   map[string]any `json:"args,omitempty"`
+	}
+	content, err := json.Marshal(struct {
+		Input  []converterConfig `json:"input"`
+		Output []converterConfig `json:"output"`
+	}{
+		Input:  []converterConfig{{Type: input, Action: lib.ActionAdd, Args: quickInputArgs(input, *inputFile)}},
+		Output: []converterConfig{{Type: output, Action: lib.ActionOutput, Args: quickOutputArgs(output, *outputFile)}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := instance.InitConfigFromBytes(content); err != nil {
+		return err
+	}
+	return instance.Run()
+}
+
+// readBatchConfigs returns the config files listed in path, skipping
+// empty lines and lines starting with #.
+func readBatchConfigs(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]string, 0)
+	for line := range strings.Lines(string(content)) {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			configs = append(configs, line)
+		}
+	}
+	return configs, nil
+}
+
 func main() {
 	flag.Parse()
+	lib.Debug = *debug
 
 	if *list {
 		lib.ListInputConverter()
@@ -28,6 +137,41 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if *envFile != "" {
+		if err := instance.LoadEnvFile(*envFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *inputFile != "" {
+		if err := runQuickConversion(instance); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *batchFile != "" {
+		configs, err := readBatchConfigs(*batchFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		errs, err := instance.RunBatch(configs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				log.Printf("❌ %v", err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Fatalf("❌ %d of %d configs failed", failed, len(configs))
+		}
+		return
+	}
+
 	if err := instance.InitConfig(*configFile); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,237 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeKVOut = "cloudflareKV"
+	descKVOut = "Convert data to Cloudflare Workers KV bulk JSON format"
+)
+
+var (
+	defaultOutputName = "cloudflare-kv.json"
+	defaultOutputDir  = filepath.Join("./", "output", "cloudflare")
+)
+
+func init() {
+	lib.RegisterOutputConfigCreator(typeKVOut, func(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+		return newKVOut(action, data)
+	})
+	lib.RegisterOutputConverter(typeKVOut, &kvOut{
+		Description: descKVOut,
+	})
+}
+
+func newKVOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		OutputName string     `json:"outputName"`
+		OutputDir  string     `json:"outputDir"`
+		Want       []string   `json:"wantedList"`
+		Exclude    []string   `json:"excludedList"`
+		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.OutputName == "" {
+		tmp.OutputName = defaultOutputName
+	}
+
+	if tmp.OutputDir == "" {
+		tmp.OutputDir = defaultOutputDir
+	}
+
+	return &kvOut{
+		Type:        typeKVOut,
+		Action:      action,
+		Description: descKVOut,
+		OutputName:  tmp.OutputName,
+		OutputDir:   tmp.OutputDir,
+		Want:        tmp.Want,
+		Exclude:     tmp.Exclude,
+		OnlyIPType:  tmp.OnlyIPType,
+
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
+	}, nil
+}
+
+type kvOut struct {
+	Type        string
+	Action      lib.Action
+	Description string
+	OutputName  string
+	OutputDir   string
+	Want        []string
+	Exclude     []string
+	OnlyIPType  lib.IPType
+
+	MinEntryPrefixes int
+
+	PostCompress   string
+	DeleteOriginal bool
+}
+
+type kvPair struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type kvValue struct {
+	Country string `json:"country"`
+}
+
+func (k *kvOut) GetType() string {
+	return k.Type
+}
+
+func (k *kvOut) GetAction() lib.Action {
+	return k.Action
+}
+
+func (k *kvOut) GetDescription() string {
+	return k.Description
+}
+
+func (k *kvOut) Output(container lib.Container) error {
+	pairs := make([]kvPair, 0, 1024)
+	owners := make(map[netip.Prefix]string, 1024)
+
+	for _, name := range k.filterAndSortList(container) {
+		entry, found := container.GetEntry(name)
+		if !found {
+			log.Printf("❌ entry %s not found\n", name)
+			continue
+		}
+
+		entryCidr, err := k.marshalPrefix(entry)
+		if err != nil {
+			return err
+		}
+
+		if k.MinEntryPrefixes > 0 && len(entryCidr) < k.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", k.Type, name, len(entryCidr), k.MinEntryPrefixes)
+			continue
+		}
+
+		value, err := json.Marshal(kvValue{Country: entry.GetName()})
+		if err != nil {
+			return err
+		}
+
+		for _, prefix := range entryCidr {
+			// A KV namespace holds one value per key, so the same prefix in two
+			// entries would make one of them silently overwrite the other
+			if owner, found := owners[prefix]; found {
+				return fmt.Errorf("❌ [type %s | action %s] prefix %s is in both entry %s and entry %s, use wantedList or excludedList to keep only one of them", k.Type, k.Action, prefix, owner, name)
+			}
+			owners[prefix] = name
+
+			pairs = append(pairs, kvPair{
+				Key:   prefix.String(),
+				Value: string(value),
+			})
+		}
+	}
+
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	kvBytes, err := json.Marshal(pairs)
+	if err != nil {
+		return err
+	}
+
+	return k.writeFile(k.OutputName, kvBytes)
+}
+
+func (k *kvOut) filterAndSortList(container lib.Container) []string {
+	excludeMap := make(map[string]bool)
+	for _, exclude := range k.Exclude {
+		if exclude = strings.ToUpper(strings.TrimSpace(exclude)); exclude != "" {
+			excludeMap[exclude] = true
+		}
+	}
+
+	wantList := make([]string, 0, len(k.Want))
+	for _, want := range k.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" && !excludeMap[want] {
+			wantList = append(wantList, want)
+		}
+	}
+
+	if len(wantList) > 0 {
+		// Sort the list
+		slices.Sort(wantList)
+		return wantList
+	}
+
+	list := make([]string, 0, 300)
+	for entry := range container.Loop() {
+		name := entry.GetName()
+		if excludeMap[name] {
+			continue
+		}
+		list = append(list, name)
+	}
+
+	// Sort the list
+	slices.Sort(list)
+
+	return list
+}
+
+func (k *kvOut) marshalPrefix(entry *lib.Entry) ([]netip.Prefix, error) {
+	switch k.OnlyIPType {
+	case lib.IPv4:
+		return entry.MarshalPrefix(lib.IgnoreIPv6)
+	case lib.IPv6:
+		return entry.MarshalPrefix(lib.IgnoreIPv4)
+	default:
+		return entry.MarshalPrefix()
+	}
+}
+
+func (k *kvOut) writeFile(filename string, kvBytes []byte) error {
+	if err := os.MkdirAll(k.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(k.OutputDir, filename), kvBytes, 0644); err != nil {
+		return err
+	}
+
+	log.Printf("✅ [%s] %s --> %s", k.Type, filename, k.OutputDir)
+
+	if err := lib.PostProcessFile(k.PostCompress, filepath.Join(k.OutputDir, filename), k.DeleteOriginal); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+func newTestContainer(t *testing.T, entries map[string][]string) lib.Container {
+	t.Helper()
+
+	container := lib.NewContainer()
+	for name, cidrs := range entries {
+		entry := lib.NewEntry(name)
+		for _, cidr := range cidrs {
+			if err := entry.AddPrefix(cidr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := container.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return container
+}
+
+func TestKVOutput(t *testing.T) {
+	container := newTestContainer(t, map[string][]string{
+		"CN": {"1.0.1.0/24"},
+		"US": {"8.8.8.0/24", "2001:db8::/32"},
+	})
+
+	out := &kvOut{Type: typeKVOut, Action: lib.ActionOutput, OutputName: defaultOutputName, OutputDir: t.TempDir()}
+	if err := out.Output(container); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out.OutputDir, out.OutputName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pairs []kvPair
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []kvPair{
+		{Key: "1.0.1.0/24", Value: `{"country":"CN"}`},
+		{Key: "8.8.8.0/24", Value: `{"country":"US"}`},
+		{Key: "2001:db8::/32", Value: `{"country":"US"}`},
+	}
+	if !slices.Equal(pairs, want) {
+		t.Fatalf("got %v, want %v", pairs, want)
+	}
+}
+
+func TestKVOutputDuplicateKey(t *testing.T) {
+	container := newTestContainer(t, map[string][]string{
+		"CN": {"1.0.1.0/24"},
+		"HK": {"1.0.1.0/24"},
+	})
+
+	out := &kvOut{Type: typeKVOut, Action: lib.ActionOutput, OutputName: defaultOutputName, OutputDir: t.TempDir()}
+	if err := out.Output(container); err == nil {
+		t.Fatal("expected an error for a prefix in two entries")
+	}
+
+	out.Exclude = []string{"HK"}
+	if err := out.Output(container); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/oschwald/geoip2-golang"
 	"github.com/oschwald/maxminddb-golang"
@@ -31,10 +32,16 @@ func init() {
 }
 
 func newDBIPLiteCountryMMDBIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		URI        string     `json:"uri"`
 		Want       []string   `json:"wantedList"`
 		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
 	}
 
 	if len(data) > 0 {
@@ -62,6 +69,8 @@ func newDBIPLiteCountryMMDBIn(action lib.Action, data json.RawMessage) (lib.Inpu
 		URI:         tmp.URI,
 		Want:        wantList,
 		OnlyIPType:  tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
 	}, nil
 }
 
@@ -72,6 +81,8 @@ type dbipLiteCountryMMDBIn struct {
 	URI         string
 	Want        map[string]bool
 	OnlyIPType  lib.IPType
+
+	TargetEntry string
 }
 
 func (d *dbipLiteCountryMMDBIn) GetType() string {
@@ -86,6 +97,10 @@ func (d *dbipLiteCountryMMDBIn) GetDescription() string {
 	return d.Description
 }
 
+func (d *dbipLiteCountryMMDBIn) GetModTime() (time.Time, error) {
+	return lib.GetModTime(d.URI)
+}
+
 func (d *dbipLiteCountryMMDBIn) Input(container lib.Container) (lib.Container, error) {
 	var content []byte
 	var err error
@@ -117,7 +132,10 @@ func (d *dbipLiteCountryMMDBIn) Input(container lib.Container) (lib.Container, e
 		ignoreIPType = lib.IgnoreIPv4
 	}
 
+	lastModified := time.Now()
 	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
 		switch d.Action {
 		case lib.ActionAdd:
 			if err := container.Add(entry, ignoreIPType); err != nil {
@@ -127,6 +145,10 @@ func (d *dbipLiteCountryMMDBIn) Input(container lib.Container) (lib.Container, e
 			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 				return nil, err
 			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, lib.ErrUnknownAction
 		}
@@ -166,6 +188,10 @@ func (d *dbipLiteCountryMMDBIn) generateEntries(content []byte, entries map[stri
 			continue
 		}
 
+		if d.TargetEntry != "" {
+			name = d.TargetEntry
+		}
+
 		entry, found := entries[name]
 		if !found {
 			entry = lib.NewEntry(name)
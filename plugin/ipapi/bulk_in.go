@@ -0,0 +1,295 @@
+package ipapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeBulkIn = "ipApiBulk"
+	descBulkIn = "Convert IP addresses looked up with the ip-api.com batch API to other formats"
+)
+
+var (
+	batchURL    = "http://ip-api.com/batch"
+	proBatchURL = "https://pro.ip-api.com/batch"
+)
+
+const (
+	batchFields = "status,message,query,countryCode"
+
+	// The batch API accepts up to 100 IP addresses per request,
+	// and up to 15 requests per minute without an API key
+	batchSize         = 100
+	freeRequestPeriod = time.Minute / 15
+	proRequestPeriod  = time.Minute / 150
+)
+
+func init() {
+	lib.RegisterInputConfigCreator(typeBulkIn, func(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+		return newBulkIn(action, data)
+	})
+	lib.RegisterInputConverter(typeBulkIn, &bulkIn{
+		Description: descBulkIn,
+	})
+}
+
+func newBulkIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		URI        string     `json:"uri"`
+		APIKey     string     `json:"apiKey"`
+		Want       []string   `json:"wantedList"`
+		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.URI == "" {
+		return nil, fmt.Errorf("❌ [type %s | action %s] uri must be specified in config", typeBulkIn, action)
+	}
+
+	// Filter want list
+	wantList := make(map[string]bool)
+	for _, want := range tmp.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" {
+			wantList[want] = true
+		}
+	}
+
+	return &bulkIn{
+		Type:        typeBulkIn,
+		Action:      action,
+		Description: descBulkIn,
+		URI:         tmp.URI,
+		APIKey:      strings.TrimSpace(tmp.APIKey),
+		Want:        wantList,
+		OnlyIPType:  tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
+	}, nil
+}
+
+type bulkIn struct {
+	Type        string
+	Action      lib.Action
+	Description string
+	URI         string
+	APIKey      string
+	Want        map[string]bool
+	OnlyIPType  lib.IPType
+
+	TargetEntry string
+}
+
+type batchResult struct {
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+	Query       string `json:"query"`
+	CountryCode string `json:"countryCode"`
+}
+
+func (b *bulkIn) GetType() string {
+	return b.Type
+}
+
+func (b *bulkIn) GetAction() lib.Action {
+	return b.Action
+}
+
+func (b *bulkIn) GetDescription() string {
+	return b.Description
+}
+
+func (b *bulkIn) Input(container lib.Container) (lib.Container, error) {
+	var content []byte
+	var err error
+	switch {
+	case strings.HasPrefix(strings.ToLower(b.URI), "http://"), strings.HasPrefix(strings.ToLower(b.URI), "https://"):
+		content, err = lib.GetRemoteURLContent(b.URI)
+	default:
+		content, err = os.ReadFile(b.URI)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := b.readIPs(content)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*lib.Entry)
+	if err := b.generateEntries(ips, entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("❌ [type %s | action %s] no entry is generated", b.Type, b.Action)
+	}
+
+	var ignoreIPType lib.IgnoreIPOption
+	switch b.OnlyIPType {
+	case lib.IPv4:
+		ignoreIPType = lib.IgnoreIPv6
+	case lib.IPv6:
+		ignoreIPType = lib.IgnoreIPv4
+	}
+
+	lastModified := time.Now()
+	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
+		switch b.Action {
+		case lib.ActionAdd:
+			if err := container.Add(entry, ignoreIPType); err != nil {
+				return nil, err
+			}
+		case lib.ActionRemove:
+			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
+				return nil, err
+			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, lib.ErrUnknownAction
+		}
+	}
+
+	return container, nil
+}
+
+// readIPs returns the IP addresses of content, one per line,
+// skipping empty lines and comments.
+func (b *bulkIn) readIPs(content []byte) ([]string, error) {
+	ips := make([]string, 0, 1024)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if _, err := netip.ParseAddr(line); err != nil {
+			return nil, fmt.Errorf("❌ [type %s | action %s] invalid IP address %s", b.Type, b.Action, line)
+		}
+		ips = append(ips, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+func (b *bulkIn) generateEntries(ips []string, entries map[string]*lib.Entry) error {
+	period := freeRequestPeriod
+	if b.APIKey != "" {
+		period = proRequestPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	failed := 0
+	for start := 0; start < len(ips); start += batchSize {
+		if start > 0 {
+			<-ticker.C
+		}
+
+		results, err := b.queryBatch(ips[start:min(start+batchSize, len(ips))])
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if result.Status != "success" {
+				log.Printf("⚠️ [%s] failed to look up %s: %s\n", b.Type, result.Query, result.Message)
+				failed++
+				continue
+			}
+
+			name := strings.ToUpper(strings.TrimSpace(result.CountryCode))
+			if name == "" {
+				continue
+			}
+
+			if len(b.Want) > 0 && !b.Want[name] {
+				continue
+			}
+
+			if b.TargetEntry != "" {
+				name = b.TargetEntry
+			}
+
+			entry, found := entries[name]
+			if !found {
+				entry = lib.NewEntry(name)
+			}
+
+			if err := entry.AddPrefix(result.Query); err != nil {
+				return err
+			}
+
+			entries[name] = entry
+		}
+	}
+
+	if failed > 0 && failed == len(ips) {
+		return fmt.Errorf("❌ [type %s | action %s] all %d lookups failed", b.Type, b.Action, failed)
+	}
+
+	return nil
+}
+
+func (b *bulkIn) queryBatch(ips []string) ([]batchResult, error) {
+	query := url.Values{}
+	query.Set("fields", batchFields)
+
+	endpoint := batchURL
+	if b.APIKey != "" {
+		endpoint = proBatchURL
+		query.Set("key", b.APIKey)
+	}
+
+	body, err := json.Marshal(ips)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post(endpoint+"?"+query.Encode(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query %s, http status code %d", endpoint, resp.StatusCode)
+	}
+
+	var results []batchResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,107 @@
+package ipapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+// newTestServer starts a stub of the batch API which looks up the posted IP
+// addresses in countries, failing those which are not found.
+func newTestServer(t *testing.T, countries map[string]string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ips []string
+		if err := json.NewDecoder(r.Body).Decode(&ips); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		results := make([]batchResult, 0, len(ips))
+		for _, ip := range ips {
+			if code, found := countries[ip]; found {
+				results = append(results, batchResult{Status: "success", Query: ip, CountryCode: code})
+			} else {
+				results = append(results, batchResult{Status: "fail", Message: "private range", Query: ip})
+			}
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	t.Cleanup(server.Close)
+
+	oldURL, oldProURL := batchURL, proBatchURL
+	batchURL, proBatchURL = server.URL, server.URL
+	t.Cleanup(func() {
+		batchURL, proBatchURL = oldURL, oldProURL
+	})
+}
+
+func newTestBulkIn(t *testing.T, ips string) lib.InputConverter {
+	t.Helper()
+
+	uri := filepath.Join(t.TempDir(), "ips.txt")
+	if err := os.WriteFile(uri, []byte(ips), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(map[string]string{"uri": uri})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := newBulkIn(lib.ActionAdd, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+func TestBulkInput(t *testing.T) {
+	newTestServer(t, map[string]string{
+		"1.1.1.1":     "AU",
+		"8.8.8.8":     "US",
+		"2001:4860::": "US",
+	})
+
+	in := newTestBulkIn(t, "# resolvers\n1.1.1.1\n8.8.8.8\n2001:4860::\n10.0.0.1\n")
+	container, err := in.Input(lib.NewContainer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"AU": {"1.1.1.1/32"},
+		"US": {"8.8.8.8/32", "2001:4860::/128"},
+	}
+	if container.Len() != len(want) {
+		t.Fatalf("got %d entries, want %d", container.Len(), len(want))
+	}
+	for name, cidrs := range want {
+		entry, found := container.GetEntry(name)
+		if !found {
+			t.Fatalf("entry %s not found", name)
+		}
+		prefixes, err := entry.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(prefixes, cidrs) {
+			t.Errorf("entry %s has %v, want %v", name, prefixes, cidrs)
+		}
+	}
+}
+
+func TestBulkInputAllFailed(t *testing.T) {
+	newTestServer(t, nil)
+
+	in := newTestBulkIn(t, "10.0.0.1\n192.168.0.1\n")
+	if _, err := in.Input(lib.NewContainer()); err == nil {
+		t.Fatal("expected an error when all lookups fail")
+	}
+}
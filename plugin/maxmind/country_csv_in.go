@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/v2fly/geoip/lib"
 )
@@ -33,12 +34,18 @@ func init() {
 }
 
 func newGeoLite2CountryCSVIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		CountryCodeFile string     `json:"country"`
 		IPv4File        string     `json:"ipv4"`
 		IPv6File        string     `json:"ipv6"`
 		Want            []string   `json:"wantedList"`
 		OnlyIPType      lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
 	}
 
 	if len(data) > 0 {
@@ -75,6 +82,8 @@ func newGeoLite2CountryCSVIn(action lib.Action, data json.RawMessage) (lib.Input
 		IPv6File:        tmp.IPv6File,
 		Want:            wantList,
 		OnlyIPType:      tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
 	}, nil
 }
 
@@ -87,6 +96,8 @@ type geoLite2CountryCSVIn struct {
 	IPv6File        string
 	Want            map[string]bool
 	OnlyIPType      lib.IPType
+
+	TargetEntry string
 }
 
 func (g *geoLite2CountryCSVIn) GetType() string {
@@ -101,6 +112,10 @@ func (g *geoLite2CountryCSVIn) GetDescription() string {
 	return g.Description
 }
 
+func (g *geoLite2CountryCSVIn) GetModTime() (time.Time, error) {
+	return lib.GetModTime(g.CountryCodeFile, g.IPv4File, g.IPv6File)
+}
+
 func (g *geoLite2CountryCSVIn) Input(container lib.Container) (lib.Container, error) {
 	ccMap, err := g.getCountryCode()
 	if err != nil {
@@ -133,7 +148,10 @@ func (g *geoLite2CountryCSVIn) Input(container lib.Container) (lib.Container, er
 		ignoreIPType = lib.IgnoreIPv4
 	}
 
+	lastModified := time.Now()
 	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
 		switch g.Action {
 		case lib.ActionAdd:
 			if err := container.Add(entry, ignoreIPType); err != nil {
@@ -143,6 +161,10 @@ func (g *geoLite2CountryCSVIn) Input(container lib.Container) (lib.Container, er
 			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 				return nil, err
 			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, lib.ErrUnknownAction
 		}
@@ -247,6 +269,10 @@ func (g *geoLite2CountryCSVIn) process(file string, ccMap map[string]string, ent
 		}
 
 		if countryCode, found := ccMap[ccID]; found {
+			if g.TargetEntry != "" {
+				countryCode = g.TargetEntry
+			}
+
 			cidrStr := strings.ToLower(strings.TrimSpace(record[0]))
 			entry, found := entries[countryCode]
 			if !found {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/oschwald/geoip2-golang"
 	"github.com/oschwald/maxminddb-golang"
@@ -31,10 +32,16 @@ func init() {
 }
 
 func newGeoLite2CountryMMDBIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		URI        string     `json:"uri"`
 		Want       []string   `json:"wantedList"`
 		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
 	}
 
 	if len(data) > 0 {
@@ -62,6 +69,8 @@ func newGeoLite2CountryMMDBIn(action lib.Action, data json.RawMessage) (lib.Inpu
 		URI:         tmp.URI,
 		Want:        wantList,
 		OnlyIPType:  tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
 	}, nil
 }
 
@@ -72,6 +81,8 @@ type geoLite2CountryMMDBIn struct {
 	URI         string
 	Want        map[string]bool
 	OnlyIPType  lib.IPType
+
+	TargetEntry string
 }
 
 func (g *geoLite2CountryMMDBIn) GetType() string {
@@ -86,6 +97,10 @@ func (g *geoLite2CountryMMDBIn) GetDescription() string {
 	return g.Description
 }
 
+func (g *geoLite2CountryMMDBIn) GetModTime() (time.Time, error) {
+	return lib.GetModTime(g.URI)
+}
+
 func (g *geoLite2CountryMMDBIn) Input(container lib.Container) (lib.Container, error) {
 	var content []byte
 	var err error
@@ -117,7 +132,10 @@ func (g *geoLite2CountryMMDBIn) Input(container lib.Container) (lib.Container, e
 		ignoreIPType = lib.IgnoreIPv4
 	}
 
+	lastModified := time.Now()
 	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
 		switch g.Action {
 		case lib.ActionAdd:
 			if err := container.Add(entry, ignoreIPType); err != nil {
@@ -127,6 +145,10 @@ func (g *geoLite2CountryMMDBIn) Input(container lib.Container) (lib.Container, e
 			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 				return nil, err
 			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, lib.ErrUnknownAction
 		}
@@ -166,6 +188,10 @@ func (g *geoLite2CountryMMDBIn) generateEntries(content []byte, entries map[stri
 			continue
 		}
 
+		if g.TargetEntry != "" {
+			name = g.TargetEntry
+		}
+
 		entry, found := entries[name]
 		if !found {
 			entry = lib.NewEntry(name)
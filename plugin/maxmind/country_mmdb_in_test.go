@@ -0,0 +1,92 @@
+package maxmind
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+// minimalMMDB returns an IPv4 MaxMind DB with a single node, which maps
+// 0.0.0.0/1 to country CN and leaves 128.0.0.0/1 empty.
+func minimalMMDB() []byte {
+	var db bytes.Buffer
+
+	// Search tree: the left record points to the first data record, the
+	// right record equals node_count and means no data
+	db.Write([]byte{0x00, 0x00, 0x11, 0x00, 0x00, 0x01})
+	db.Write(make([]byte, 16))
+
+	// Data section: {"country": {"iso_code": "CN"}}
+	db.Write([]byte{0xe1, 0x47})
+	db.WriteString("country")
+	db.Write([]byte{0xe1, 0x48})
+	db.WriteString("iso_code")
+	db.Write([]byte{0x42})
+	db.WriteString("CN")
+
+	// Metadata
+	db.WriteString("\xab\xcd\xefMaxMind.com")
+	db.Write([]byte{0xe9})
+	writeString := func(s string) {
+		db.WriteByte(0x40 | byte(len(s)))
+		db.WriteString(s)
+	}
+	writeString("node_count")
+	db.Write([]byte{0xc1, 0x01})
+	writeString("record_size")
+	db.Write([]byte{0xa1, 0x18})
+	writeString("ip_version")
+	db.Write([]byte{0xa1, 0x04})
+	writeString("database_type")
+	writeString("GeoLite2-Country")
+	writeString("languages")
+	db.Write([]byte{0x00, 0x04})
+	writeString("binary_format_major_version")
+	db.Write([]byte{0xa1, 0x02})
+	writeString("binary_format_minor_version")
+	db.Write([]byte{0xa0})
+	writeString("build_epoch")
+	db.Write([]byte{0x00, 0x02})
+	writeString("description")
+	db.Write([]byte{0xe0})
+
+	return db.Bytes()
+}
+
+func FuzzMaxmindMMDBInput(f *testing.F) {
+	in := &geoLite2CountryMMDBIn{
+		Type:   typeGeoLite2CountryMMDBIn,
+		Action: lib.ActionAdd,
+	}
+
+	mmdb := minimalMMDB()
+	entries := make(map[string]*lib.Entry)
+	if err := in.generateEntries(mmdb, entries); err != nil {
+		f.Fatalf("generateEntries of the minimal MMDB: %v", err)
+	}
+	if _, found := entries["CN"]; !found || len(entries) != 1 {
+		f.Fatalf("generateEntries of the minimal MMDB = %v, want only CN", entries)
+	}
+
+	f.Add(mmdb)
+	f.Add([]byte{})
+	f.Add(mmdb[:len(mmdb)/2])
+	f.Add(mmdb[:len(mmdb)-1])
+	f.Add(mmdb[22:])
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		entries := make(map[string]*lib.Entry)
+		if err := in.generateEntries(content, entries); err != nil {
+			return
+		}
+
+		for name, entry := range entries {
+			for prefix := range entry.Prefixes(lib.IPBoth) {
+				if !prefix.IsValid() {
+					t.Fatalf("invalid prefix %v in entry %s", prefix, name)
+				}
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package plaintext
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/v2fly/geoip/lib"
 )
@@ -29,6 +32,10 @@ func init() {
 }
 
 func newTextIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		Name       string     `json:"name"`
 		URI        string     `json:"uri"`
@@ -39,6 +46,9 @@ func newTextIn(action lib.Action, data json.RawMessage) (lib.InputConverter, err
 
 		RemovePrefixesInLine []string `json:"removePrefixesInLine"`
 		RemoveSuffixesInLine []string `json:"removeSuffixesInLine"`
+
+		TargetEntry  string `json:"targetEntry"`
+		ParallelLoad int    `json:"parallelLoad"`
 	}
 
 	if len(data) > 0 {
@@ -51,7 +61,7 @@ func newTextIn(action lib.Action, data json.RawMessage) (lib.InputConverter, err
 		if tmp.Name == "" {
 			return nil, fmt.Errorf("❌ [type %s | action %s] missing inputDir or name", typeTextIn, action)
 		}
-		if tmp.URI == "" && len(tmp.IPOrCIDR) == 0 {
+		if tmp.URI == "" && len(tmp.IPOrCIDR) == 0 && action != lib.ActionClear {
 			return nil, fmt.Errorf("❌ [type %s | action %s] missing uri or ipOrCIDR", typeTextIn, action)
 		}
 	} else if tmp.Name != "" || tmp.URI != "" || len(tmp.IPOrCIDR) > 0 {
@@ -79,6 +89,9 @@ func newTextIn(action lib.Action, data json.RawMessage) (lib.InputConverter, err
 
 		RemovePrefixesInLine: tmp.RemovePrefixesInLine,
 		RemoveSuffixesInLine: tmp.RemoveSuffixesInLine,
+
+		TargetEntry:  strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
+		ParallelLoad: tmp.ParallelLoad,
 	}, nil
 }
 
@@ -95,6 +108,9 @@ type textIn struct {
 
 	RemovePrefixesInLine []string
 	RemoveSuffixesInLine []string
+
+	TargetEntry  string
+	ParallelLoad int
 }
 
 func (t *textIn) GetType() string {
@@ -109,6 +125,10 @@ func (t *textIn) GetDescription() string {
 	return t.Description
 }
 
+func (t *textIn) GetModTime() (time.Time, error) {
+	return lib.GetModTime(t.InputDir, t.URI)
+}
+
 func (t *textIn) Input(container lib.Container) (lib.Container, error) {
 	entries := make(map[string]*lib.Entry)
 	var err error
@@ -130,7 +150,7 @@ func (t *textIn) Input(container lib.Container) (lib.Container, error) {
 
 		fallthrough
 
-	case t.Name != "" && len(t.IPOrCIDR) > 0:
+	case t.Name != "" && (len(t.IPOrCIDR) > 0 || t.Action == lib.ActionClear):
 		err = t.appendIPOrCIDR(t.IPOrCIDR, t.Name, entries)
 
 	default:
@@ -153,7 +173,10 @@ func (t *textIn) Input(container lib.Container) (lib.Container, error) {
 		return nil, fmt.Errorf("❌ [type %s | action %s] no entry is generated", t.Type, t.Action)
 	}
 
+	lastModified := time.Now()
 	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
 		switch t.Action {
 		case lib.ActionAdd:
 			if err := container.Add(entry, ignoreIPType); err != nil {
@@ -163,6 +186,10 @@ func (t *textIn) Input(container lib.Container) (lib.Container, error) {
 			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 				return nil, err
 			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, lib.ErrUnknownAction
 		}
@@ -172,6 +199,7 @@ func (t *textIn) Input(container lib.Container) (lib.Container, error) {
 }
 
 func (t *textIn) walkDir(dir string, entries map[string]*lib.Entry) error {
+	paths := make([]string, 0, 300)
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -180,14 +208,81 @@ func (t *textIn) walkDir(dir string, entries map[string]*lib.Entry) error {
 			return nil
 		}
 
-		if err := t.walkLocalFile(path, "", entries); err != nil {
-			return err
-		}
+		paths = append(paths, path)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if t.ParallelLoad <= 1 {
+		for _, path := range paths {
+			if err := t.walkLocalFile(path, "", entries); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return t.walkFilesParallel(paths, entries)
+}
+
+// walkFilesParallel parses up to ParallelLoad files at the same time, each
+// into its own entries, and merges them into entries under a mutex.
+func (t *textIn) walkFilesParallel(paths []string, entries map[string]*lib.Entry) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, t.ParallelLoad)
+
+	for _, path := range paths {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fileEntries := make(map[string]*lib.Entry)
+			err := t.walkLocalFile(path, "", fileEntries)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				err = t.mergeEntries(fileEntries, entries)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
 
-	return err
+	return errors.Join(errs...)
+}
+
+func (t *textIn) mergeEntries(from, to map[string]*lib.Entry) error {
+	for name, entry := range from {
+		existing, found := to[name]
+		switch {
+		case !found:
+			to[name] = entry
+		case t.TargetEntry == "":
+			return fmt.Errorf("found duplicated list %s", name)
+		default:
+			for prefix := range entry.Prefixes(lib.IPBoth) {
+				if err := existing.AddPrefix(prefix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
 }
 
 func (t *textIn) walkLocalFile(path, name string, entries map[string]*lib.Entry) error {
@@ -215,11 +310,20 @@ func (t *textIn) walkLocalFile(path, name string, entries map[string]*lib.Entry)
 	if len(t.Want) > 0 && !t.Want[entryName] {
 		return nil
 	}
-	if _, found := entries[entryName]; found {
+
+	// All files go into the same list when targetEntry is set
+	if t.TargetEntry != "" {
+		entryName = t.TargetEntry
+	}
+
+	entry, found := entries[entryName]
+	switch {
+	case found && t.TargetEntry == "":
 		return fmt.Errorf("found duplicated list %s", entryName)
+	case !found:
+		entry = lib.NewEntry(entryName)
 	}
 
-	entry := lib.NewEntry(entryName)
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -251,6 +355,10 @@ func (t *textIn) walkRemoteFile(url, name string, entries map[string]*lib.Entry)
 		return nil
 	}
 
+	if t.TargetEntry != "" {
+		name = t.TargetEntry
+	}
+
 	entry := lib.NewEntry(name)
 	if err := t.scanFile(resp.Body, entry); err != nil {
 		return err
@@ -299,6 +407,9 @@ func (t *textIn) scanFile(reader io.Reader, entry *lib.Entry) error {
 
 func (t *textIn) appendIPOrCIDR(ipOrCIDR []string, name string, entries map[string]*lib.Entry) error {
 	name = strings.ToUpper(name)
+	if t.TargetEntry != "" {
+		name = t.TargetEntry
+	}
 
 	entry, found := entries[name]
 	if !found {
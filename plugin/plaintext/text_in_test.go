@@ -0,0 +1,39 @@
+package plaintext
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+func FuzzTextInputParse(f *testing.F) {
+	f.Add([]byte(""))
+	f.Add([]byte(" \t\n\r\n   \n"))
+	f.Add([]byte("1.1.1.1\n10.0.0.0/8 # comment\n2001:db8::/32 // comment\n"))
+	f.Add([]byte("300.1.1.1\n10.0.0.0/33\n::ffff:zz/96\nnot-a-cidr\n"))
+	f.Add([]byte(strings.Repeat("1", 4096) + "\n"))
+	f.Add([]byte("1.1.1.0/24" + strings.Repeat(" ", 4096) + "# comment\n"))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		in := &textIn{
+			Type:   typeTextIn,
+			Action: lib.ActionAdd,
+			Name:   "FUZZ",
+		}
+
+		entry := lib.NewEntry(in.Name)
+		if err := in.scanFile(bytes.NewReader(content), entry); err != nil {
+			return
+		}
+
+		// Every accepted line must leave the entry in a state that can be
+		// turned into valid prefixes
+		for prefix := range entry.Prefixes(lib.IPBoth) {
+			if !prefix.IsValid() {
+				t.Fatalf("invalid prefix %v parsed from %q", prefix, content)
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package plaintext
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -31,6 +32,10 @@ func init() {
 }
 
 func newTextOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		OutputDir  string     `json:"outputDir"`
 		OutputExt  string     `json:"outputExtension"`
@@ -38,8 +43,13 @@ func newTextOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, e
 		Exclude    []string   `json:"excludedList"`
 		OnlyIPType lib.IPType `json:"onlyIPType"`
 
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
 		AddPrefixInLine string `json:"addPrefixInLine"`
 		AddSuffixInLine string `json:"addSuffixInLine"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
 	}
 
 	if len(data) > 0 {
@@ -66,8 +76,13 @@ func newTextOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, e
 		Exclude:     tmp.Exclude,
 		OnlyIPType:  tmp.OnlyIPType,
 
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
 		AddPrefixInLine: tmp.AddPrefixInLine,
 		AddSuffixInLine: tmp.AddSuffixInLine,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
 	}, nil
 }
 
@@ -81,8 +96,13 @@ type textOut struct {
 	Exclude     []string
 	OnlyIPType  lib.IPType
 
+	MinEntryPrefixes int
+
 	AddPrefixInLine string
 	AddSuffixInLine string
+
+	PostCompress   string
+	DeleteOriginal bool
 }
 
 func (t *textOut) GetType() string {
@@ -110,6 +130,11 @@ func (t *textOut) Output(container lib.Container) error {
 			return err
 		}
 
+		if t.MinEntryPrefixes > 0 && len(cidrList) < t.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", t.Type, name, len(cidrList), t.MinEntryPrefixes)
+			continue
+		}
+
 		filename := strings.ToLower(entry.GetName()) + t.OutputExt
 		if err := t.writeFile(filename, cidrList); err != nil {
 			return err
@@ -203,5 +228,9 @@ func (t *textOut) writeFile(filename string, cidrList []string) error {
 
 	log.Printf("✅ [%s] %s --> %s", t.Type, filename, t.OutputDir)
 
+	if err := lib.PostProcessFile(t.PostCompress, filepath.Join(t.OutputDir, filename), t.DeleteOriginal); err != nil {
+		return err
+	}
+
 	return nil
 }
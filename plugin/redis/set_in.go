@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeSetIn = "redisSet"
+	descSetIn = "Convert CIDR stored in Redis sets to other formats"
+)
+
+var (
+	defaultRedisAddr  = "127.0.0.1:6379"
+	defaultKeyPattern = "geoip:*"
+)
+
+func init() {
+	lib.RegisterInputConfigCreator(typeSetIn, func(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+		return newSetIn(action, data)
+	})
+	lib.RegisterInputConverter(typeSetIn, &setIn{
+		Description: descSetIn,
+	})
+}
+
+func newSetIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		RedisAddr     string     `json:"redisAddr"`
+		RedisPassword string     `json:"redisPassword"`
+		RedisDB       int        `json:"redisDB"`
+		KeyPattern    string     `json:"keyPattern"`
+		Want          []string   `json:"wantedList"`
+		OnlyIPType    lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.RedisAddr == "" {
+		tmp.RedisAddr = defaultRedisAddr
+	}
+
+	if tmp.KeyPattern == "" {
+		tmp.KeyPattern = defaultKeyPattern
+	}
+
+	// Filter want list
+	wantList := make(map[string]bool)
+	for _, want := range tmp.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" {
+			wantList[want] = true
+		}
+	}
+
+	return &setIn{
+		Type:          typeSetIn,
+		Action:        action,
+		Description:   descSetIn,
+		RedisAddr:     tmp.RedisAddr,
+		RedisPassword: tmp.RedisPassword,
+		RedisDB:       tmp.RedisDB,
+		KeyPattern:    tmp.KeyPattern,
+		Want:          wantList,
+		OnlyIPType:    tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
+	}, nil
+}
+
+type setIn struct {
+	Type          string
+	Action        lib.Action
+	Description   string
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	KeyPattern    string
+	Want          map[string]bool
+	OnlyIPType    lib.IPType
+
+	TargetEntry string
+}
+
+func (s *setIn) GetType() string {
+	return s.Type
+}
+
+func (s *setIn) GetAction() lib.Action {
+	return s.Action
+}
+
+func (s *setIn) GetDescription() string {
+	return s.Description
+}
+
+func (s *setIn) Input(container lib.Container) (lib.Container, error) {
+	entries := make(map[string]*lib.Entry)
+	if err := s.generateEntries(entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("❌ [type %s | action %s] no entry is generated", s.Type, s.Action)
+	}
+
+	var ignoreIPType lib.IgnoreIPOption
+	switch s.OnlyIPType {
+	case lib.IPv4:
+		ignoreIPType = lib.IgnoreIPv6
+	case lib.IPv6:
+		ignoreIPType = lib.IgnoreIPv4
+	}
+
+	lastModified := time.Now()
+	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
+		switch s.Action {
+		case lib.ActionAdd:
+			if err := container.Add(entry, ignoreIPType); err != nil {
+				return nil, err
+			}
+		case lib.ActionRemove:
+			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
+				return nil, err
+			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, lib.ErrUnknownAction
+		}
+	}
+
+	return container, nil
+}
+
+// generateEntries reads the members of all sets whose keys match KeyPattern.
+// The entry name of a set is the part of its key after the literal prefix of
+// KeyPattern, e.g. CN for the key geoip:cn and the pattern geoip:*.
+func (s *setIn) generateEntries(entries map[string]*lib.Entry) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     s.RedisAddr,
+		Password: s.RedisPassword,
+		DB:       s.RedisDB,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	keys := make([]string, 0)
+	iter := client.Scan(ctx, 0, s.KeyPattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	// SCAN may return a key more than once
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	keyPrefix := s.KeyPattern
+	if i := strings.IndexAny(keyPrefix, `*?[\`); i >= 0 {
+		keyPrefix = keyPrefix[:i]
+	}
+
+	for _, key := range keys {
+		name := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(key, keyPrefix)))
+		if name == "" {
+			continue
+		}
+
+		if len(s.Want) > 0 && !s.Want[name] {
+			continue
+		}
+
+		members, err := client.SMembers(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("❌ [type %s | action %s] failed to read key %s: %w", s.Type, s.Action, key, err)
+		}
+
+		if s.TargetEntry != "" {
+			name = s.TargetEntry
+		}
+
+		entry, found := entries[name]
+		if !found {
+			entry = lib.NewEntry(name)
+		}
+
+		for _, member := range members {
+			if err := entry.AddPrefix(member); err != nil {
+				return fmt.Errorf("❌ [type %s | action %s] invalid CIDR %s in key %s: %w", s.Type, s.Action, member, key, err)
+			}
+		}
+
+		entries[name] = entry
+	}
+
+	return nil
+}
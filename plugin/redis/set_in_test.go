@@ -0,0 +1,186 @@
+package redis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+// newTestServer starts a minimal Redis server which serves sets from data.
+// It only understands the commands sent by setIn, and answers HELLO with an
+// error so that the client falls back to RESP2.
+func newTestServer(t *testing.T, data map[string][]string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, data)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveTestConn(conn net.Conn, data map[string][]string) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case "SCAN":
+			// Return every key twice, as SCAN may do
+			pattern := "*"
+			if i := slices.Index(args, "match"); i >= 0 && i+1 < len(args) {
+				pattern = args[i+1]
+			}
+			var keys []string
+			for key := range data {
+				if matched, _ := path.Match(pattern, key); matched {
+					keys = append(keys, key, key)
+				}
+			}
+			reply = "*2\r\n$1\r\n0\r\n" + respArray(keys)
+		case "SMEMBERS":
+			reply = respArray(data[args[1]])
+		default:
+			reply = "+OK\r\n"
+		}
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings.
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("invalid command %q", line)
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func respArray(items []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(items))
+	for _, item := range items {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(item), item)
+	}
+	return b.String()
+}
+
+func TestSetInput(t *testing.T) {
+	addr := newTestServer(t, map[string][]string{
+		"geoip:cn":  {"1.0.1.0/24", "2001:db8::/32"},
+		"geoip:us":  {"8.8.8.0/24"},
+		"other:key": {"9.9.9.0/24"},
+	})
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want map[string][]string
+	}{
+		{
+			name: "all keys",
+			args: map[string]any{},
+			want: map[string][]string{
+				"CN": {"1.0.1.0/24", "2001:db8::/32"},
+				"US": {"8.8.8.0/24"},
+			},
+		},
+		{
+			name: "wantedList and onlyIPType",
+			args: map[string]any{"wantedList": []string{"cn"}, "onlyIPType": "ipv4"},
+			want: map[string][]string{
+				"CN": {"1.0.1.0/24"},
+			},
+		},
+		{
+			name: "targetEntry",
+			args: map[string]any{"targetEntry": "all"},
+			want: map[string][]string{
+				"ALL": {"1.0.1.0/24", "8.8.8.0/24", "2001:db8::/32"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["redisAddr"] = addr
+			data, err := json.Marshal(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in, err := newSetIn(lib.ActionAdd, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			container, err := in.Input(lib.NewContainer())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if container.Len() != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", container.Len(), len(tt.want))
+			}
+			for name, want := range tt.want {
+				entry, found := container.GetEntry(name)
+				if !found {
+					t.Fatalf("entry %s not found", name)
+				}
+				got, err := entry.MarshalText()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("entry %s has %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -23,6 +23,10 @@ func init() {
 }
 
 func newCutter(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		Want       []string   `json:"wantedList"`
 		OnlyIPType lib.IPType `json:"onlyIPType"`
@@ -34,8 +38,8 @@ func newCutter(action lib.Action, data json.RawMessage) (lib.InputConverter, err
 		}
 	}
 
-	if action != lib.ActionRemove {
-		return nil, fmt.Errorf("type %s only supports `remove` action", typeCutter)
+	if action != lib.ActionRemove && action != lib.ActionClear {
+		return nil, fmt.Errorf("type %s only supports `remove` and `clear` actions", typeCutter)
 	}
 
 	// Filter want list
@@ -88,11 +92,16 @@ func (c *cutter) Input(container lib.Container) (lib.Container, error) {
 		ignoreIPType = lib.IgnoreIPv4
 	}
 
+	rCase := lib.CaseRemoveEntry
+	if c.Action == lib.ActionClear {
+		rCase = lib.CaseClearEntry
+	}
+
 	for entry := range container.Loop() {
 		if len(c.Want) > 0 && !c.Want[entry.GetName()] {
 			continue
 		}
-		if err := container.Remove(entry, lib.CaseRemoveEntry, ignoreIPType); err != nil {
+		if err := container.Remove(entry, rCase, ignoreIPType); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,209 @@
+package special
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeManifest = "containerManifest"
+	descManifest = "Write a JSON summary of the entries and their prefix counts"
+)
+
+var (
+	defaultManifestName = "manifest.json"
+	defaultManifestDir  = filepath.Join("./", "output")
+)
+
+func init() {
+	lib.RegisterOutputConfigCreator(typeManifest, func(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+		return newManifest(action, data)
+	})
+	lib.RegisterOutputConverter(typeManifest, &manifest{
+		Description: descManifest,
+	})
+}
+
+func newManifest(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		OutputName string   `json:"outputName"`
+		OutputDir  string   `json:"outputDir"`
+		Want       []string `json:"wantedList"`
+		Exclude    []string `json:"excludedList"`
+
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.OutputName == "" {
+		tmp.OutputName = defaultManifestName
+	}
+
+	if tmp.OutputDir == "" {
+		tmp.OutputDir = defaultManifestDir
+	}
+
+	return &manifest{
+		Type:        typeManifest,
+		Action:      action,
+		Description: descManifest,
+		OutputName:  tmp.OutputName,
+		OutputDir:   tmp.OutputDir,
+		Want:        tmp.Want,
+		Exclude:     tmp.Exclude,
+
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
+	}, nil
+}
+
+type manifest struct {
+	Type        string
+	Action      lib.Action
+	Description string
+	OutputName  string
+	OutputDir   string
+	Want        []string
+	Exclude     []string
+
+	MinEntryPrefixes int
+
+	PostCompress   string
+	DeleteOriginal bool
+}
+
+type manifestFile struct {
+	Generated time.Time       `json:"generated"`
+	Entries   []manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	Name         string `json:"name"`
+	IPv4Prefixes int    `json:"ipv4Prefixes"`
+	IPv6Prefixes int    `json:"ipv6Prefixes"`
+}
+
+func (m *manifest) GetType() string {
+	return m.Type
+}
+
+func (m *manifest) GetAction() lib.Action {
+	return m.Action
+}
+
+func (m *manifest) GetDescription() string {
+	return m.Description
+}
+
+func (m *manifest) Output(container lib.Container) error {
+	file := manifestFile{
+		Generated: time.Now().UTC(),
+		Entries:   make([]manifestEntry, 0, container.Len()),
+	}
+
+	for _, name := range m.filterAndSortList(container) {
+		entry, found := container.GetEntry(name)
+		if !found {
+			log.Printf("❌ entry %s not found\n", name)
+			continue
+		}
+
+		me := manifestEntry{Name: entry.GetName()}
+		for prefix := range entry.Prefixes(lib.IPBoth) {
+			if prefix.Addr().Is4() {
+				me.IPv4Prefixes++
+			} else {
+				me.IPv6Prefixes++
+			}
+		}
+
+		if count := me.IPv4Prefixes + me.IPv6Prefixes; m.MinEntryPrefixes > 0 && count < m.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", m.Type, name, count, m.MinEntryPrefixes)
+			continue
+		}
+		file.Entries = append(file.Entries, me)
+	}
+
+	manifestBytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return m.writeFile(m.OutputName, manifestBytes)
+}
+
+func (m *manifest) filterAndSortList(container lib.Container) []string {
+	excludeMap := make(map[string]bool)
+	for _, exclude := range m.Exclude {
+		if exclude = strings.ToUpper(strings.TrimSpace(exclude)); exclude != "" {
+			excludeMap[exclude] = true
+		}
+	}
+
+	wantList := make([]string, 0, len(m.Want))
+	for _, want := range m.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" && !excludeMap[want] {
+			wantList = append(wantList, want)
+		}
+	}
+
+	if len(wantList) > 0 {
+		// Sort the list
+		slices.Sort(wantList)
+		return wantList
+	}
+
+	list := make([]string, 0, 300)
+	for entry := range container.Loop() {
+		name := entry.GetName()
+		if excludeMap[name] {
+			continue
+		}
+		list = append(list, name)
+	}
+
+	// Sort the list
+	slices.Sort(list)
+
+	return list
+}
+
+func (m *manifest) writeFile(filename string, manifestBytes []byte) error {
+	if err := os.MkdirAll(m.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(m.OutputDir, filename), manifestBytes, 0644); err != nil {
+		return err
+	}
+
+	log.Printf("✅ [%s] %s --> %s", m.Type, filename, m.OutputDir)
+
+	if err := lib.PostProcessFile(m.PostCompress, filepath.Join(m.OutputDir, filename), m.DeleteOriginal); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package special
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+func TestManifestOutput(t *testing.T) {
+	container, err := lib.NewContainerFromMap(map[string][]string{
+		"CN":      {"1.0.1.0/24", "1.0.2.0/23", "240e::/20"},
+		"US":      {"8.8.8.0/24"},
+		"PRIVATE": {"10.0.0.0/8", "fc00::/7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"outputDir":        t.TempDir(),
+		"excludedList":     []string{"private"},
+		"minEntryPrefixes": 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := newManifest(lib.ActionOutput, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := out.(*manifest)
+	if err := m.Output(container); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(m.OutputDir, defaultManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got manifestFile
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// US is skipped by minEntryPrefixes, and PRIVATE is excluded
+	want := []manifestEntry{{Name: "CN", IPv4Prefixes: 2, IPv6Prefixes: 1}}
+	if !slices.Equal(got.Entries, want) {
+		t.Errorf("entries = %v, want %v", got.Entries, want)
+	}
+	if got.Generated.IsZero() {
+		t.Error("generated time is not set")
+	}
+}
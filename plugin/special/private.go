@@ -2,6 +2,9 @@ package special
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/v2fly/geoip/lib"
 )
@@ -46,8 +49,14 @@ func init() {
 }
 
 func newPrivate(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
 	}
 
 	if len(data) > 0 {
@@ -61,6 +70,8 @@ func newPrivate(action lib.Action, data json.RawMessage) (lib.InputConverter, er
 		Action:      action,
 		Description: descPrivate,
 		OnlyIPType:  tmp.OnlyIPType,
+
+		TargetEntry: strings.TrimSpace(tmp.TargetEntry),
 	}, nil
 }
 
@@ -69,6 +80,8 @@ type private struct {
 	Action      lib.Action
 	Description string
 	OnlyIPType  lib.IPType
+
+	TargetEntry string
 }
 
 func (p *private) GetType() string {
@@ -84,9 +97,14 @@ func (p *private) GetDescription() string {
 }
 
 func (p *private) Input(container lib.Container) (lib.Container, error) {
-	entry, found := container.GetEntry(entryNamePrivate)
+	name := entryNamePrivate
+	if p.TargetEntry != "" {
+		name = p.TargetEntry
+	}
+
+	entry, found := container.GetEntry(name)
 	if !found {
-		entry = lib.NewEntry(entryNamePrivate)
+		entry = lib.NewEntry(name)
 	}
 
 	for _, cidr := range privateCIDRs {
@@ -95,6 +113,8 @@ func (p *private) Input(container lib.Container) (lib.Container, error) {
 		}
 	}
 
+	entry.SetLastModified(time.Now())
+
 	var ignoreIPType lib.IgnoreIPOption
 	switch p.OnlyIPType {
 	case lib.IPv4:
@@ -112,6 +132,10 @@ func (p *private) Input(container lib.Container) (lib.Container, error) {
 		if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 			return nil, err
 		}
+	case lib.ActionClear:
+		if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, lib.ErrUnknownAction
 	}
@@ -0,0 +1,308 @@
+package special
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeHTMLReport = "htmlReport"
+	descHTMLReport = "Write an HTML report with the prefix counts and coverage of the entries"
+)
+
+var (
+	defaultHTMLReportName = "report.html"
+	defaultHTMLReportDir  = filepath.Join("./", "output")
+)
+
+// The number of entries shown in the chart of the report
+const reportChartSize = 20
+
+func init() {
+	lib.RegisterOutputConfigCreator(typeHTMLReport, func(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+		return newHTMLReport(action, data)
+	})
+	lib.RegisterOutputConverter(typeHTMLReport, &htmlReport{
+		Description: descHTMLReport,
+	})
+}
+
+func newHTMLReport(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		OutputName string   `json:"outputName"`
+		OutputDir  string   `json:"outputDir"`
+		Want       []string `json:"wantedList"`
+		Exclude    []string `json:"excludedList"`
+
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.OutputName == "" {
+		tmp.OutputName = defaultHTMLReportName
+	}
+
+	if tmp.OutputDir == "" {
+		tmp.OutputDir = defaultHTMLReportDir
+	}
+
+	return &htmlReport{
+		Type:        typeHTMLReport,
+		Action:      action,
+		Description: descHTMLReport,
+		OutputName:  tmp.OutputName,
+		OutputDir:   tmp.OutputDir,
+		Want:        tmp.Want,
+		Exclude:     tmp.Exclude,
+
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
+	}, nil
+}
+
+type htmlReport struct {
+	Type        string
+	Action      lib.Action
+	Description string
+	OutputName  string
+	OutputDir   string
+	Want        []string
+	Exclude     []string
+
+	MinEntryPrefixes int
+
+	PostCompress   string
+	DeleteOriginal bool
+}
+
+type reportData struct {
+	Generated   string
+	Rows        []reportRow
+	ChartLabels []string
+	ChartCounts []int
+}
+
+type reportRow struct {
+	Name         string
+	IPv4Prefixes int
+	IPv6Prefixes int
+	IPv4Coverage string
+	IPv6Coverage string
+	IPv4Percent  float64
+	IPv6Percent  float64
+}
+
+func (h *htmlReport) GetType() string {
+	return h.Type
+}
+
+func (h *htmlReport) GetAction() lib.Action {
+	return h.Action
+}
+
+func (h *htmlReport) GetDescription() string {
+	return h.Description
+}
+
+func (h *htmlReport) Output(container lib.Container) error {
+	data := reportData{
+		Generated: time.Now().UTC().Format(time.RFC3339),
+		Rows:      make([]reportRow, 0, container.Len()),
+	}
+
+	for _, name := range h.filterAndSortList(container) {
+		entry, found := container.GetEntry(name)
+		if !found {
+			log.Printf("❌ entry %s not found\n", name)
+			continue
+		}
+
+		row, err := newReportRow(entry)
+		if err != nil {
+			return err
+		}
+		if count := row.IPv4Prefixes + row.IPv6Prefixes; h.MinEntryPrefixes > 0 && count < h.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", h.Type, name, count, h.MinEntryPrefixes)
+			continue
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	// The chart shows the entries with the most prefixes
+	top := slices.Clone(data.Rows)
+	slices.SortStableFunc(top, func(a, b reportRow) int {
+		return (b.IPv4Prefixes + b.IPv6Prefixes) - (a.IPv4Prefixes + a.IPv6Prefixes)
+	})
+	for _, row := range top[:min(reportChartSize, len(top))] {
+		data.ChartLabels = append(data.ChartLabels, row.Name)
+		data.ChartCounts = append(data.ChartCounts, row.IPv4Prefixes+row.IPv6Prefixes)
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	return h.writeFile(h.OutputName, buf.Bytes())
+}
+
+// newReportRow counts the prefixes of entry. The coverage is the share of
+// the whole IPv4 or IPv6 address space covered by the entry.
+func newReportRow(entry *lib.Entry) (reportRow, error) {
+	ipv4Coverage, err := entry.IPv4Coverage()
+	if err != nil {
+		return reportRow{}, err
+	}
+	ipv6Coverage, err := entry.IPv6Coverage()
+	if err != nil {
+		return reportRow{}, err
+	}
+
+	row := reportRow{
+		Name:         entry.GetName(),
+		IPv4Prefixes: entry.IPv4Len(),
+		IPv6Prefixes: entry.IPv6Len(),
+		IPv4Percent:  ipv4Coverage * 100,
+		IPv6Percent:  ipv6Coverage * 100,
+	}
+	row.IPv4Coverage = fmt.Sprintf("%.4f%%", row.IPv4Percent)
+	row.IPv6Coverage = fmt.Sprintf("%.4f%%", row.IPv6Percent)
+	return row, nil
+}
+
+func (h *htmlReport) filterAndSortList(container lib.Container) []string {
+	excludeMap := make(map[string]bool)
+	for _, exclude := range h.Exclude {
+		if exclude = strings.ToUpper(strings.TrimSpace(exclude)); exclude != "" {
+			excludeMap[exclude] = true
+		}
+	}
+
+	wantList := make([]string, 0, len(h.Want))
+	for _, want := range h.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" && !excludeMap[want] {
+			wantList = append(wantList, want)
+		}
+	}
+
+	if len(wantList) > 0 {
+		// Sort the list
+		slices.Sort(wantList)
+		return wantList
+	}
+
+	list := make([]string, 0, 300)
+	for entry := range container.Loop() {
+		name := entry.GetName()
+		if excludeMap[name] {
+			continue
+		}
+		list = append(list, name)
+	}
+
+	// Sort the list
+	slices.Sort(list)
+
+	return list
+}
+
+func (h *htmlReport) writeFile(filename string, reportBytes []byte) error {
+	if err := os.MkdirAll(h.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(h.OutputDir, filename), reportBytes, 0644); err != nil {
+		return err
+	}
+
+	log.Printf("✅ [%s] %s --> %s", h.Type, filename, h.OutputDir)
+
+	if err := lib.PostProcessFile(h.PostCompress, filepath.Join(h.OutputDir, filename), h.DeleteOriginal); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>GeoIP report</title>
+<script src="https://cdn.jsdelivr.net/npm/chart.js"></script>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { cursor: pointer; background: #f0f0f0; }
+td:first-child, th:first-child { text-align: left; }
+#chart { max-width: 960px; margin-bottom: 2em; }
+</style>
+</head>
+<body>
+<h1>GeoIP report</h1>
+<p>Generated at {{.Generated}}</p>
+<div id="chart"><canvas id="topChart"></canvas></div>
+<table id="report">
+<thead>
+<tr><th>Country</th><th>IPv4 Prefix Count</th><th>IPv6 Prefix Count</th><th>Approx IPv4 Coverage %</th><th>Approx IPv6 Coverage %</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td><td data-value="{{.IPv4Prefixes}}">{{.IPv4Prefixes}}</td><td data-value="{{.IPv6Prefixes}}">{{.IPv6Prefixes}}</td><td data-value="{{.IPv4Percent}}">{{.IPv4Coverage}}</td><td data-value="{{.IPv6Percent}}">{{.IPv6Coverage}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+new Chart(document.getElementById("topChart"), {
+  type: "bar",
+  data: {
+    labels: {{.ChartLabels}},
+    datasets: [{ label: "Prefix count", data: {{.ChartCounts}} }]
+  }
+});
+
+document.querySelectorAll("#report th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#report tbody");
+    var rows = Array.from(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column], y = b.cells[column];
+      var result = column === 0
+        ? x.textContent.localeCompare(y.textContent)
+        : parseFloat(x.dataset.value) - parseFloat(y.dataset.value);
+      return ascending ? result : -result;
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
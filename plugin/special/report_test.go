@@ -0,0 +1,78 @@
+package special
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+func TestNewReportRow(t *testing.T) {
+	entry := lib.NewEntry("TEST")
+	for _, cidr := range []string{"10.0.0.0/8", "11.0.0.0/8", "2000::/3"} {
+		if err := entry.AddPrefix(cidr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	row, err := newReportRow(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10.0.0.0/8 and 11.0.0.0/8 merge into 10.0.0.0/7, 1/128 of the IPv4 space
+	want := reportRow{
+		Name:         "TEST",
+		IPv4Prefixes: 1,
+		IPv6Prefixes: 1,
+		IPv4Coverage: "0.7812%",
+		IPv6Coverage: "12.5000%",
+		IPv4Percent:  0.78125,
+		IPv6Percent:  12.5,
+	}
+	if row != want {
+		t.Errorf("newReportRow = %+v, want %+v", row, want)
+	}
+}
+
+func TestHTMLReportOutput(t *testing.T) {
+	container, err := lib.NewContainerFromMap(map[string][]string{
+		"CN": {"1.0.1.0/24", "1.0.4.0/22"},
+		"US": {"8.8.8.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"outputDir":        t.TempDir(),
+		"minEntryPrefixes": 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := newHTMLReport(lib.ActionOutput, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := out.(*htmlReport)
+	if err := h.Output(container); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(h.OutputDir, defaultHTMLReportName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// US is skipped by minEntryPrefixes
+	if !strings.Contains(string(content), "<td>CN</td>") {
+		t.Error("report has no row for CN")
+	}
+	if strings.Contains(string(content), "<td>US</td>") {
+		t.Error("report has a row for US, which has fewer prefixes than minEntryPrefixes")
+	}
+}
@@ -2,6 +2,9 @@ package special
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/v2fly/geoip/lib"
 )
@@ -26,10 +29,26 @@ func init() {
 }
 
 func newTest(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		TargetEntry string `json:"targetEntry"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
 	return &test{
 		Type:        typeTest,
 		Action:      action,
 		Description: descTest,
+
+		TargetEntry: strings.TrimSpace(tmp.TargetEntry),
 	}, nil
 }
 
@@ -37,6 +56,8 @@ type test struct {
 	Type        string
 	Action      lib.Action
 	Description string
+
+	TargetEntry string
 }
 
 func (t *test) GetType() string {
@@ -52,13 +73,20 @@ func (t *test) GetDescription() string {
 }
 
 func (t *test) Input(container lib.Container) (lib.Container, error) {
-	entry := lib.NewEntry(entryNameTest)
+	name := entryNameTest
+	if t.TargetEntry != "" {
+		name = t.TargetEntry
+	}
+
+	entry := lib.NewEntry(name)
 	for _, cidr := range testCIDRs {
 		if err := entry.AddPrefix(cidr); err != nil {
 			return nil, err
 		}
 	}
 
+	entry.SetLastModified(time.Now())
+
 	switch t.Action {
 	case lib.ActionAdd:
 		if err := container.Add(entry); err != nil {
@@ -68,6 +96,10 @@ func (t *test) Input(container lib.Container) (lib.Container, error) {
 		if err := container.Remove(entry, lib.CaseRemovePrefix); err != nil {
 			return nil, err
 		}
+	case lib.ActionClear:
+		if err := container.Remove(entry, lib.CaseClearEntry); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, lib.ErrUnknownAction
 	}
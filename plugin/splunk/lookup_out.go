@@ -0,0 +1,241 @@
+package splunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+const (
+	typeLookupOut = "splunkLookup"
+	descLookupOut = "Convert data to pipe-delimited Splunk lookup table format"
+)
+
+var (
+	defaultOutputName = "geoip-lookup.csv"
+	defaultOutputDir  = filepath.Join("./", "output", "splunk")
+)
+
+const lookupHeader = "CIDR|country_code|country_name"
+
+func init() {
+	lib.RegisterOutputConfigCreator(typeLookupOut, func(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+		return newLookupOut(action, data)
+	})
+	lib.RegisterOutputConverter(typeLookupOut, &lookupOut{
+		Description: descLookupOut,
+	})
+}
+
+func newLookupOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	var tmp struct {
+		OutputName     string     `json:"outputName"`
+		OutputDir      string     `json:"outputDir"`
+		Want           []string   `json:"wantedList"`
+		Exclude        []string   `json:"excludedList"`
+		OneFilePerList bool       `json:"oneFilePerList"`
+		OnlyIPType     lib.IPType `json:"onlyIPType"`
+
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return nil, err
+		}
+	}
+
+	if tmp.OutputName == "" {
+		tmp.OutputName = defaultOutputName
+	}
+
+	if tmp.OutputDir == "" {
+		tmp.OutputDir = defaultOutputDir
+	}
+
+	return &lookupOut{
+		Type:           typeLookupOut,
+		Action:         action,
+		Description:    descLookupOut,
+		OutputName:     tmp.OutputName,
+		OutputDir:      tmp.OutputDir,
+		Want:           tmp.Want,
+		Exclude:        tmp.Exclude,
+		OneFilePerList: tmp.OneFilePerList,
+		OnlyIPType:     tmp.OnlyIPType,
+
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
+	}, nil
+}
+
+type lookupOut struct {
+	Type           string
+	Action         lib.Action
+	Description    string
+	OutputName     string
+	OutputDir      string
+	Want           []string
+	Exclude        []string
+	OneFilePerList bool
+	OnlyIPType     lib.IPType
+
+	MinEntryPrefixes int
+
+	PostCompress   string
+	DeleteOriginal bool
+}
+
+func (l *lookupOut) GetType() string {
+	return l.Type
+}
+
+func (l *lookupOut) GetAction() lib.Action {
+	return l.Action
+}
+
+func (l *lookupOut) GetDescription() string {
+	return l.Description
+}
+
+func (l *lookupOut) Output(container lib.Container) error {
+	var buf bytes.Buffer
+	buf.WriteString(lookupHeader + "\n")
+	updated := false
+
+	for _, name := range l.filterAndSortList(container) {
+		entry, found := container.GetEntry(name)
+		if !found {
+			log.Printf("❌ entry %s not found\n", name)
+			continue
+		}
+
+		cidrList, err := l.marshalText(entry)
+		if err != nil {
+			return err
+		}
+
+		if l.MinEntryPrefixes > 0 && len(cidrList) < l.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", l.Type, name, len(cidrList), l.MinEntryPrefixes)
+			continue
+		}
+
+		if l.OneFilePerList {
+			var entryBuf bytes.Buffer
+			entryBuf.WriteString(lookupHeader + "\n")
+			writeRows(&entryBuf, entry.GetName(), cidrList)
+
+			filename := strings.ToLower(entry.GetName()) + ".csv"
+			if err := l.writeFile(filename, entryBuf.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		writeRows(&buf, entry.GetName(), cidrList)
+		updated = true
+	}
+
+	if !l.OneFilePerList && updated {
+		if err := l.writeFile(l.OutputName, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeRows writes a CIDR|country_code|country_name row for each CIDR.
+// The country name is empty if name is not an ISO 3166 country code.
+func writeRows(buf *bytes.Buffer, name string, cidrList []string) {
+	countryName := countryNames[name]
+	for _, cidr := range cidrList {
+		buf.WriteString(cidr)
+		buf.WriteString("|")
+		buf.WriteString(name)
+		buf.WriteString("|")
+		buf.WriteString(countryName)
+		buf.WriteString("\n")
+	}
+}
+
+func (l *lookupOut) filterAndSortList(container lib.Container) []string {
+	excludeMap := make(map[string]bool)
+	for _, exclude := range l.Exclude {
+		if exclude = strings.ToUpper(strings.TrimSpace(exclude)); exclude != "" {
+			excludeMap[exclude] = true
+		}
+	}
+
+	wantList := make([]string, 0, len(l.Want))
+	for _, want := range l.Want {
+		if want = strings.ToUpper(strings.TrimSpace(want)); want != "" && !excludeMap[want] {
+			wantList = append(wantList, want)
+		}
+	}
+
+	if len(wantList) > 0 {
+		// Sort the list
+		slices.Sort(wantList)
+		return wantList
+	}
+
+	list := make([]string, 0, 300)
+	for entry := range container.Loop() {
+		name := entry.GetName()
+		if excludeMap[name] {
+			continue
+		}
+		list = append(list, name)
+	}
+
+	// Sort the list
+	slices.Sort(list)
+
+	return list
+}
+
+func (l *lookupOut) marshalText(entry *lib.Entry) ([]string, error) {
+	switch l.OnlyIPType {
+	case lib.IPv4:
+		return entry.MarshalText(lib.IgnoreIPv6)
+	case lib.IPv6:
+		return entry.MarshalText(lib.IgnoreIPv4)
+	default:
+		return entry.MarshalText()
+	}
+}
+
+func (l *lookupOut) writeFile(filename string, lookupBytes []byte) error {
+	if err := os.MkdirAll(l.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(l.OutputDir, filename), lookupBytes, 0644); err != nil {
+		return err
+	}
+
+	log.Printf("✅ [%s] %s --> %s", l.Type, filename, l.OutputDir)
+
+	if err := lib.PostProcessFile(l.PostCompress, filepath.Join(l.OutputDir, filename), l.DeleteOriginal); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package splunk
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/v2fly/geoip/lib"
+)
+
+func newTestLookupOut(t *testing.T, args map[string]any) *lookupOut {
+	t.Helper()
+
+	args["outputDir"] = t.TempDir()
+	data, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := newLookupOut(lib.ActionOutput, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.(*lookupOut)
+}
+
+func TestLookupOutput(t *testing.T) {
+	container, err := lib.NewContainerFromMap(map[string][]string{
+		"CN":      {"1.0.1.0/24", "240e::/20"},
+		"US":      {"8.8.8.0/24"},
+		"PRIVATE": {"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		args  map[string]any
+		files map[string]string
+	}{
+		{
+			name: "one file",
+			args: map[string]any{"excludedList": []string{"private"}},
+			files: map[string]string{
+				defaultOutputName: "CIDR|country_code|country_name\n" +
+					"1.0.1.0/24|CN|China\n" +
+					"240e::/20|CN|China\n" +
+					"8.8.8.0/24|US|United States\n",
+			},
+		},
+		{
+			name: "one file per list",
+			args: map[string]any{"oneFilePerList": true, "onlyIPType": "ipv4", "wantedList": []string{"cn", "private"}},
+			files: map[string]string{
+				"cn.csv":      "CIDR|country_code|country_name\n1.0.1.0/24|CN|China\n",
+				"private.csv": "CIDR|country_code|country_name\n10.0.0.0/8|PRIVATE|\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := newTestLookupOut(t, tt.args)
+			if err := out.Output(container); err != nil {
+				t.Fatal(err)
+			}
+
+			for filename, want := range tt.files {
+				got, err := os.ReadFile(filepath.Join(out.OutputDir, filename))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("%s =\n%s\nwant\n%s", filename, got, want)
+				}
+			}
+
+			files, err := os.ReadDir(out.OutputDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != len(tt.files) {
+				t.Errorf("got %d files, want %d", len(files), len(tt.files))
+			}
+		})
+	}
+}
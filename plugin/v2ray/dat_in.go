@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/v2fly/geoip/lib"
 	"google.golang.org/protobuf/proto"
@@ -28,10 +29,16 @@ func init() {
 }
 
 func newGeoIPDatIn(action lib.Action, data json.RawMessage) (lib.InputConverter, error) {
+	if !lib.ValidInputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		URI        string     `json:"uri"`
 		Want       []string   `json:"wantedList"`
 		OnlyIPType lib.IPType `json:"onlyIPType"`
+
+		TargetEntry string `json:"targetEntry"`
 	}
 
 	if len(data) > 0 {
@@ -59,6 +66,8 @@ func newGeoIPDatIn(action lib.Action, data json.RawMessage) (lib.InputConverter,
 		URI:         tmp.URI,
 		Want:        wantList,
 		OnlyIPType:  tmp.OnlyIPType,
+
+		TargetEntry: strings.ToUpper(strings.TrimSpace(tmp.TargetEntry)),
 	}, nil
 }
 
@@ -69,6 +78,8 @@ type geoipDatIn struct {
 	URI         string
 	Want        map[string]bool
 	OnlyIPType  lib.IPType
+
+	TargetEntry string
 }
 
 func (g *geoipDatIn) GetType() string {
@@ -83,6 +94,10 @@ func (g *geoipDatIn) GetDescription() string {
 	return g.Description
 }
 
+func (g *geoipDatIn) GetModTime() (time.Time, error) {
+	return lib.GetModTime(g.URI)
+}
+
 func (g *geoipDatIn) Input(container lib.Container) (lib.Container, error) {
 	entries := make(map[string]*lib.Entry)
 	var err error
@@ -110,7 +125,10 @@ func (g *geoipDatIn) Input(container lib.Container) (lib.Container, error) {
 		ignoreIPType = lib.IgnoreIPv4
 	}
 
+	lastModified := time.Now()
 	for _, entry := range entries {
+		entry.SetLastModified(lastModified)
+
 		switch g.Action {
 		case lib.ActionAdd:
 			if err := container.Add(entry, ignoreIPType); err != nil {
@@ -120,6 +138,10 @@ func (g *geoipDatIn) Input(container lib.Container) (lib.Container, error) {
 			if err := container.Remove(entry, lib.CaseRemovePrefix, ignoreIPType); err != nil {
 				return nil, err
 			}
+		case lib.ActionClear:
+			if err := container.Remove(entry, lib.CaseClearEntry, ignoreIPType); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, lib.ErrUnknownAction
 		}
@@ -178,6 +200,10 @@ func (g *geoipDatIn) generateEntries(reader io.Reader, entries map[string]*lib.E
 			continue
 		}
 
+		if g.TargetEntry != "" {
+			name = g.TargetEntry
+		}
+
 		entry, found := entries[name]
 		if !found {
 			entry = lib.NewEntry(name)
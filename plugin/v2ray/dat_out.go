@@ -35,6 +35,10 @@ func init() {
 }
 
 func newGeoIPDatOut(action lib.Action, data json.RawMessage) (lib.OutputConverter, error) {
+	if !lib.ValidOutputAction(action) {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
 	var tmp struct {
 		OutputName     string     `json:"outputName"`
 		OutputDir      string     `json:"outputDir"`
@@ -42,6 +46,11 @@ func newGeoIPDatOut(action lib.Action, data json.RawMessage) (lib.OutputConverte
 		Exclude        []string   `json:"excludedList"`
 		OneFilePerList bool       `json:"oneFilePerList"`
 		OnlyIPType     lib.IPType `json:"onlyIPType"`
+
+		MinEntryPrefixes int `json:"minEntryPrefixes"`
+
+		PostCompress   string `json:"postCompress"`
+		DeleteOriginal bool   `json:"deleteOriginal"`
 	}
 
 	if len(data) > 0 {
@@ -68,6 +77,11 @@ func newGeoIPDatOut(action lib.Action, data json.RawMessage) (lib.OutputConverte
 		Exclude:        tmp.Exclude,
 		OneFilePerList: tmp.OneFilePerList,
 		OnlyIPType:     tmp.OnlyIPType,
+
+		MinEntryPrefixes: tmp.MinEntryPrefixes,
+
+		PostCompress:   tmp.PostCompress,
+		DeleteOriginal: tmp.DeleteOriginal,
 	}, nil
 }
 
@@ -81,6 +95,11 @@ type geoipDatOut struct {
 	Exclude        []string
 	OneFilePerList bool
 	OnlyIPType     lib.IPType
+
+	MinEntryPrefixes int
+
+	PostCompress   string
+	DeleteOriginal bool
 }
 
 func (g *geoipDatOut) GetType() string {
@@ -111,6 +130,12 @@ func (g *geoipDatOut) Output(container lib.Container) error {
 		if err != nil {
 			return err
 		}
+
+		if g.MinEntryPrefixes > 0 && len(geoIP.Cidr) < g.MinEntryPrefixes {
+			log.Printf("⚠️ [%s] skip entry %s: %d prefixes, fewer than minEntryPrefixes %d\n", g.Type, name, len(geoIP.Cidr), g.MinEntryPrefixes)
+			continue
+		}
+
 		geoIPList.Entry = append(geoIPList.Entry, geoIP)
 		updated = true
 
@@ -232,5 +257,9 @@ func (g *geoipDatOut) writeFile(filename string, geoIPBytes []byte) error {
 
 	log.Printf("✅ [%s] %s --> %s", g.Type, filename, g.OutputDir)
 
+	if err := lib.PostProcessFile(g.PostCompress, filepath.Join(g.OutputDir, filename), g.DeleteOriginal); err != nil {
+		return err
+	}
+
 	return nil
 }